@@ -0,0 +1,109 @@
+package valf
+
+// Annotation keys used by Unit, Hint and Label.
+const (
+	AnnotationUnit  = "unit"
+	AnnotationHint  = "hint"
+	AnnotationLabel = "label"
+)
+
+// Display hints commonly used with Hint.
+const (
+	HintHex     = "hex"
+	HintBase64  = "base64"
+	HintPercent = "percent"
+)
+
+// Annotation is a piece of metadata attached to a value, e.g. a unit or
+// a display hint. Encoders and dashboards may use annotations to render
+// values properly.
+type Annotation struct {
+	Key   string
+	Value string
+}
+
+// Unit returns an Annotation with the unit of a value, e.g. "ms" or "bytes".
+func Unit(unit string) Annotation {
+	return Annotation{AnnotationUnit, unit}
+}
+
+// Hint returns an Annotation with the display hint of a value, e.g. HintHex.
+func Hint(hint string) Annotation {
+	return Annotation{AnnotationHint, hint}
+}
+
+// Label returns an Annotation with the semantic label of a value.
+func Label(label string) Annotation {
+	return Annotation{AnnotationLabel, label}
+}
+
+// Annotated returns a new Value which wraps v with the given annotations.
+// Visitors implementing AnnotatedVisitor get both the annotations and the
+// inner value, other visitors just visit the inner value. Annotating an
+// annotated value merges the annotations, later ones take precedence.
+//
+// The returned value is const if v is const, so annotations must not be
+// modified after the call.
+func Annotated(v Value, annotations ...Annotation) Value {
+	if len(annotations) == 0 {
+		return v
+	}
+
+	if v.bits.Type() == TypeAnnotated {
+		a := v.vAny.(*annotated)
+		n := len(a.annotations)
+		v, annotations = a.value, append(a.annotations[:n:n], annotations...)
+	}
+
+	return Value{bits: bits(TypeAnnotated) | v.bits&bitsConst, vAny: &annotated{v, annotations}}
+}
+
+// Annotations returns annotations of v or nil if v is not annotated.
+func (v Value) Annotations() []Annotation {
+	if v.bits.Type() != TypeAnnotated {
+		return nil
+	}
+
+	return v.vAny.(*annotated).annotations
+}
+
+// Annotation returns the value of the annotation with the given key.
+// If there are several annotations with the key, the last one is returned.
+func (v Value) Annotation(key string) (string, bool) {
+	annotations := v.Annotations()
+	for i := len(annotations) - 1; i >= 0; i-- {
+		if annotations[i].Key == key {
+			return annotations[i].Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Unannotated returns the inner value of an annotated value or v itself otherwise.
+func (v Value) Unannotated() Value {
+	if v.bits.Type() != TypeAnnotated {
+		return v
+	}
+
+	return v.vAny.(*annotated).value
+}
+
+type annotated struct {
+	value       Value
+	annotations []Annotation
+}
+
+func (v Value) acceptAnnotatedVisitor(visitor Visitor) {
+	a := v.vAny.(*annotated)
+	if av, ok := visitor.(AnnotatedVisitor); ok {
+		av.VisitAnnotated(a.value, a.annotations)
+	} else {
+		a.value.AcceptVisitor(visitor)
+	}
+}
+
+// reannotate returns v wrapped with annotations of the annotated value a.
+func reannotate(a Value, v Value) Value {
+	return Annotated(v, a.vAny.(*annotated).annotations...)
+}
@@ -0,0 +1,81 @@
+package valf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockAnnotatedVisitor struct {
+	mockVisitor
+	value       Value
+	annotations []Annotation
+	visited     bool
+}
+
+func newMockAnnotatedVisitor(t *testing.T) *mockAnnotatedVisitor {
+	return &mockAnnotatedVisitor{mockVisitor: mockVisitor{t}}
+}
+
+func (v *mockAnnotatedVisitor) VisitAnnotated(value Value, annotations []Annotation) {
+	v.value = value
+	v.annotations = annotations
+	v.visited = true
+}
+
+func TestValueAnnotated(t *testing.T) {
+	value := Annotated(Uint64(255), Hint(HintHex), Unit("bytes"))
+	require.Equal(t, TypeAnnotated, value.Type())
+	require.Equal(t, true, value.Const())
+	require.Equal(t, false, Annotated(Strings([]string{"a"}), Label("tags")).Const())
+
+	visitor := newMockAnnotatedVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, Uint64(255), visitor.value)
+	require.Equal(t, []Annotation{{AnnotationHint, HintHex}, {AnnotationUnit, "bytes"}}, visitor.annotations)
+
+	uintVisitor := newMockUint64Visitor(t)
+	value.AcceptVisitor(uintVisitor)
+	require.Equal(t, true, uintVisitor.visited)
+	require.Equal(t, uint64(255), uintVisitor.value)
+
+	unit, ok := value.Annotation(AnnotationUnit)
+	require.Equal(t, true, ok)
+	require.Equal(t, "bytes", unit)
+	_, ok = value.Annotation(AnnotationLabel)
+	require.Equal(t, false, ok)
+	require.Equal(t, Uint64(255), value.Unannotated())
+	require.Equal(t, Uint64(255), Annotated(Uint64(255)))
+	require.Equal(t, []Annotation(nil), Uint64(255).Annotations())
+}
+
+func TestValueAnnotatedMerge(t *testing.T) {
+	value := Annotated(Annotated(Int(1), Unit("ms"), Label("a")), Unit("s"))
+	require.Equal(t, Int(1), value.Unannotated())
+	require.Len(t, value.Annotations(), 3)
+
+	unit, _ := value.Annotation(AnnotationUnit)
+	require.Equal(t, "s", unit)
+}
+
+func TestValueAnnotatedSnapshot(t *testing.T) {
+	v := []string{"a", "b"}
+	annotations := []Annotation{Label("tags")}
+	value := Annotated(Strings(v), annotations...).Snapshot()
+	v[0] = "x"
+	annotations[0] = Label("changed")
+	require.Equal(t, true, value.Const())
+	require.Equal(t, ConstStrings([]string{"a", "b"}), value.Unannotated())
+	require.Equal(t, []Annotation{Label("tags")}, value.Annotations())
+}
+
+func TestValueAnnotatedRedact(t *testing.T) {
+	r := NewRedactor(RedactTypes(MaskWith("***"), TypeString))
+	value := r.Redact(Annotated(String("secret"), Label("token")))
+
+	visitor := newMockAnnotatedVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, String("***"), visitor.value)
+	require.Equal(t, []Annotation{Label("token")}, visitor.annotations)
+}
@@ -0,0 +1,293 @@
+package valf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// anyConverter converts the data of an interface value of a specific type
+// to a Value. For pointer types p is the pointer itself, for other types
+// p points to the value.
+type anyConverter func(p unsafe.Pointer, isConst bool) Value
+
+// anyConverters caches converters by type descriptor so that reflection
+// is used only once per type. A nil converter means that the type has no
+// special representation and is kept as TypeAny.
+var anyConverters sync.Map // map[unsafe.Pointer]anyConverter
+
+// eface is the memory layout of an empty interface.
+type eface struct {
+	typ  unsafe.Pointer
+	data unsafe.Pointer
+}
+
+// anyOfType returns a Value for types not handled by the type switches of
+// Any and ConstAny, e.g. named types and pointers.
+func anyOfType(v interface{}, isConst bool) Value {
+	e := (*eface)(unsafe.Pointer(&v))
+
+	c, ok := anyConverters.Load(e.typ)
+	if !ok {
+		c, _ = anyConverters.LoadOrStore(e.typ, newAnyConverter(reflect.TypeOf(v)))
+	}
+	if convert := c.(anyConverter); convert != nil {
+		return convert(e.data, isConst)
+	}
+
+	if isConst {
+		return Value{bits: bits(TypeAny) | bitsConst, vAny: v}
+	}
+
+	return Value{bits: bits(TypeAny), vAny: v}
+}
+
+func newAnyConverter(t reflect.Type) anyConverter {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		// Such types used to be kept as Any values, so the ones implementing
+		// Snapshotter keep being snapshotted by it. Named scalars are
+		// converted regardless of Snapshotter as they always were.
+		if t.Implements(snapshotterType) {
+			return nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		convert := scalarConverter(t.Elem())
+		if convert == nil {
+			return nil
+		}
+
+		return func(p unsafe.Pointer, isConst bool) Value {
+			if p == nil {
+				return Null()
+			}
+
+			return convert(p, isConst)
+		}
+	case reflect.Slice:
+		convert := sliceConverter(t.Elem())
+		if convert == nil {
+			return nil
+		}
+
+		return func(p unsafe.Pointer, isConst bool) Value {
+			s := (*sliceHeader)(p)
+
+			return convert(s.data, s.len, isConst)
+		}
+	case reflect.Array:
+		if isUUIDType(t) {
+			return scalarConverter(t)
+		}
+
+		convert := sliceConverter(t.Elem())
+		if convert == nil {
+			return nil
+		}
+
+		n := t.Len()
+
+		// Arrays stored in interfaces are immutable,
+		// so they can be used as const slices without copying.
+		return func(p unsafe.Pointer, _ bool) Value {
+			return convert(p, n, true)
+		}
+	}
+
+	return scalarConverter(t)
+}
+
+// scalarConverter returns a converter for types with a scalar underlying type.
+func scalarConverter(t reflect.Type) anyConverter {
+	switch t.Kind() {
+	case reflect.String:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return String(*(*string)(p))
+		}
+	case reflect.Bool:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Bool(*(*bool)(p))
+		}
+	case reflect.Int:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Int(*(*int)(p))
+		}
+	case reflect.Int8:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Int8(*(*int8)(p))
+		}
+	case reflect.Int16:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Int16(*(*int16)(p))
+		}
+	case reflect.Int32:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Int32(*(*int32)(p))
+		}
+	case reflect.Int64:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Int64(*(*int64)(p))
+		}
+	case reflect.Uint:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Uint(*(*uint)(p))
+		}
+	case reflect.Uint8:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Uint8(*(*uint8)(p))
+		}
+	case reflect.Uint16:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Uint16(*(*uint16)(p))
+		}
+	case reflect.Uint32:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Uint32(*(*uint32)(p))
+		}
+	case reflect.Uint64:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Uint64(*(*uint64)(p))
+		}
+	case reflect.Float32:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Float32(*(*float32)(p))
+		}
+	case reflect.Float64:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Float64(*(*float64)(p))
+		}
+	case reflect.Complex64:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Complex64(*(*complex64)(p))
+		}
+	case reflect.Complex128:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Complex128(*(*complex128)(p))
+		}
+	case reflect.Uintptr:
+		return func(p unsafe.Pointer, _ bool) Value {
+			return Uintptr(*(*uintptr)(p))
+		}
+	case reflect.Array:
+		if isUUIDType(t) {
+			return func(p unsafe.Pointer, _ bool) Value {
+				return UUID(*(*[16]byte)(p))
+			}
+		}
+	}
+
+	return nil
+}
+
+// isUUIDType reports whether t is a named [16]byte type with a name ending
+// with UUID like the ones provided by the most popular UUID packages.
+func isUUIDType(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && strings.HasSuffix(strings.ToUpper(t.Name()), "UUID") &&
+		t.Len() == 16 && t.Elem().Kind() == reflect.Uint8
+}
+
+// uuidTypes caches results of isUUIDType by type descriptor.
+var uuidTypes sync.Map // map[unsafe.Pointer]bool
+
+// anyUUID returns a UUID Value if v is a UUID. UUID types usually implement
+// fmt.Stringer, so Any and ConstAny check them before Stringers.
+func anyUUID(v interface{}) (Value, bool) {
+	e := (*eface)(unsafe.Pointer(&v))
+
+	ok, found := uuidTypes.Load(e.typ)
+	if !found {
+		ok, _ = uuidTypes.LoadOrStore(e.typ, isUUIDType(reflect.TypeOf(v)))
+	}
+	if !ok.(bool) {
+		return Value{}, false
+	}
+
+	return UUID(*(*[16]byte)(e.data)), true
+}
+
+// sliceHeader is the memory layout of a slice.
+type sliceHeader struct {
+	data unsafe.Pointer
+	len  int
+	cap  int
+}
+
+// typedSliceConverter converts n items of a specific type starting at data to a Value.
+type typedSliceConverter func(data unsafe.Pointer, n int, isConst bool) Value
+
+var (
+	snapshotterType = reflect.TypeOf((*Snapshotter)(nil)).Elem()
+	stringerType    = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+	durationType    = reflect.TypeOf(time.Duration(0))
+)
+
+// sliceConverter returns a converter for slices and arrays of items with
+// a scalar underlying type. Items implementing fmt.Stringer or error are
+// not converted since their text representation would be lost otherwise.
+func sliceConverter(elem reflect.Type) typedSliceConverter {
+	if elem == durationType {
+		return typedSlice(Durations, ConstDurations)
+	}
+	if elem.Implements(stringerType) || elem.Implements(errorType) {
+		return nil
+	}
+
+	switch elem.Kind() {
+	case reflect.String:
+		return typedSlice(Strings, ConstStrings)
+	case reflect.Bool:
+		return typedSlice(Bools, ConstBools)
+	case reflect.Int:
+		return typedSlice(Ints, ConstInts)
+	case reflect.Int8:
+		return typedSlice(Ints8, ConstInts8)
+	case reflect.Int16:
+		return typedSlice(Ints16, ConstInts16)
+	case reflect.Int32:
+		return typedSlice(Ints32, ConstInts32)
+	case reflect.Int64:
+		return typedSlice(Ints64, ConstInts64)
+	case reflect.Uint:
+		return typedSlice(Uints, ConstUints)
+	case reflect.Uint8:
+		return typedSlice(Bytes, ConstBytes)
+	case reflect.Uint16:
+		return typedSlice(Uints16, ConstUints16)
+	case reflect.Uint32:
+		return typedSlice(Uints32, ConstUints32)
+	case reflect.Uint64:
+		return typedSlice(Uints64, ConstUints64)
+	case reflect.Float32:
+		return typedSlice(Floats32, ConstFloats32)
+	case reflect.Float64:
+		return typedSlice(Floats64, ConstFloats64)
+	case reflect.Complex64:
+		return typedSlice(Complexes64, ConstComplexes64)
+	case reflect.Complex128:
+		return typedSlice(Complexes128, ConstComplexes128)
+	case reflect.Uintptr:
+		return typedSlice(Uintptrs, ConstUintptrs)
+	}
+
+	return nil
+}
+
+// typedSlice returns a converter reinterpreting the memory as a slice of T
+// which is possible since named types share the layout of their underlying types.
+func typedSlice[T any](newValue, newConstValue func([]T) Value) typedSliceConverter {
+	return func(data unsafe.Pointer, n int, isConst bool) Value {
+		s := unsafe.Slice((*T)(data), n)
+		if isConst {
+			return newConstValue(s)
+		}
+
+		return newValue(s)
+	}
+}
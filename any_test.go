@@ -0,0 +1,171 @@
+package valf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testStruct struct {
+	a int
+}
+
+func TestValueAnyCustomPtr(t *testing.T) {
+	i := customInt(42)
+	s := customString("s")
+	f := customFloat32(1.5)
+	require.Equal(t, Int(42), Any(&i))
+	require.Equal(t, Int(42), ConstAny(&i))
+	require.Equal(t, String("s"), Any(&s))
+	require.Equal(t, Float32(1.5), ConstAny(&f))
+	require.Equal(t, Null(), Any((*customInt)(nil)))
+	require.Equal(t, Null(), ConstAny((*customString)(nil)))
+
+	u := testUUID{1, 2, 3}
+	require.Equal(t, UUID(u), Any(&u))
+}
+
+func TestValueAnyUnknownType(t *testing.T) {
+	v := &testStruct{1}
+	require.Equal(t, Value{bits: bits(TypeAny), vAny: v}, Any(v))
+	require.Equal(t, Value{bits: bits(TypeAny) | bitsConst, vAny: v}, ConstAny(v))
+	require.Equal(t, Value{bits: bits(TypeAny) | bitsConst, vAny: testStruct{2}}, ConstAny(testStruct{2}))
+}
+
+type testScalarSnapshotter int
+
+func (s testScalarSnapshotter) TakeSnapshot() interface{} {
+	return int(s) + 1
+}
+
+type testPointerSnapshotter int
+
+func (s *testPointerSnapshotter) TakeSnapshot() interface{} {
+	return int(*s) + 1
+}
+
+func TestValueAnySnapshotter(t *testing.T) {
+	require.Equal(t, Int(1), Any(testScalarSnapshotter(1)))
+
+	p := testPointerSnapshotter(1)
+	value := Any(&p)
+	require.Equal(t, TypeAny, value.Type())
+	require.Equal(t, ConstAny(2), value.Snapshot())
+
+	value = Any(testSnapshotter{1})
+	require.Equal(t, TypeAny, value.Type())
+	require.Equal(t, ConstAny([]int{1}), value.Snapshot())
+}
+
+func TestValueAnyAllocations(t *testing.T) {
+	i := 42
+	ci := customInt(1000)
+	u := testUUID{1}
+	values := []interface{}{customInt(1000), customString("text"), customFloat64(0.5), &i, &ci, testUUID{1}, testStringerUUID{1}, &u}
+	for _, v := range values {
+		Any(v)
+		require.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+			_ = Any(v)
+			_ = ConstAny(v)
+		}))
+	}
+}
+
+func BenchmarkValueAnyInt(b *testing.B) {
+	var v interface{} = 1000
+	for i := 0; i != b.N; i++ {
+		_ = Any(v)
+	}
+}
+
+func BenchmarkValueAnyCustomInt(b *testing.B) {
+	var v interface{} = customInt(1000)
+	for i := 0; i != b.N; i++ {
+		_ = Any(v)
+	}
+}
+
+func BenchmarkValueAnyCustomString(b *testing.B) {
+	var v interface{} = customString("text")
+	for i := 0; i != b.N; i++ {
+		_ = Any(v)
+	}
+}
+
+func BenchmarkValueAnyIntPtr(b *testing.B) {
+	n := 1000
+	var v interface{} = &n
+	for i := 0; i != b.N; i++ {
+		_ = Any(v)
+	}
+}
+
+func BenchmarkValueAnyCustomIntPtr(b *testing.B) {
+	n := customInt(1000)
+	var v interface{} = &n
+	for i := 0; i != b.N; i++ {
+		_ = Any(v)
+	}
+}
+
+func BenchmarkValueAnyUnknownType(b *testing.B) {
+	var v interface{} = &testStruct{1}
+	for i := 0; i != b.N; i++ {
+		_ = Any(v)
+	}
+}
+
+type customInts []int
+
+type customStrings []customString
+
+type customDurations []time.Duration
+
+type testStringerInt int
+
+func (i testStringerInt) String() string {
+	return "stringer"
+}
+
+func TestValueAnyCustomSlice(t *testing.T) {
+	ints := customInts{1, 2}
+	value := Any(ints)
+	require.Equal(t, Ints([]int{1, 2}), value)
+	require.Equal(t, ConstInts([]int{1, 2}), ConstAny(ints))
+
+	ints[0] = 42
+	visitor := newMockIntsVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []int{42, 2}, visitor.value)
+
+	require.Equal(t, Strings([]string{"a"}), Any(customStrings{"a"}))
+	require.Equal(t, Ints8([]int8{1}), Any([]customInt8{1}))
+	require.Equal(t, Bytes([]byte{1}), Any([]customUint8{1}))
+	require.Equal(t, Floats64([]float64{0.5}), Any([]customFloat64{0.5}))
+	require.Equal(t, Durations([]time.Duration{time.Second}), Any(customDurations{time.Second}))
+	require.Equal(t, Ints([]int(nil)), Any(customInts(nil)))
+	require.Equal(t, TypeAny, Any([]testStringerInt{1}).Type())
+	require.Equal(t, TypeAny, Any([]*int{nil}).Type())
+}
+
+func TestValueAnyFixedSizeArray(t *testing.T) {
+	value := Any([4]float32{1, 2, 3, 4})
+	require.Equal(t, TypeFloats32, value.Type())
+	require.Equal(t, true, value.Const())
+
+	visitor := newMockFloats32Visitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []float32{1, 2, 3, 4}, visitor.value)
+
+	require.Equal(t, ConstBytes([]byte{1, 2, 3}), Any([3]byte{1, 2, 3}))
+	require.Equal(t, ConstStrings([]string{"a", "b"}), ConstAny([2]string{"a", "b"}))
+	require.Equal(t, UUID(testUUID{1}), Any(testUUID{1}))
+}
+
+func TestValueAnyCustomSliceSnapshot(t *testing.T) {
+	ints := customInts{1, 2}
+	value := Any(ints).Snapshot()
+	ints[0] = 42
+	require.Equal(t, ConstInts([]int{1, 2}), value)
+}
@@ -0,0 +1,127 @@
+package valf
+
+import (
+	"unsafe"
+)
+
+// Arena is a region of memory which SnapshotAll uses to store copies of
+// bytes, typed slices, strings slices and items of snapshotted arrays and
+// objects. It packs many small copies into a few large buffers, so all
+// values of a record can be snapshotted with a handful of allocations.
+//
+// Lifetime contract: values snapshotted into an Arena stay valid until
+// the Arena is reset. After Reset the Arena reuses its buffers, so values
+// snapshotted into it before must not be used anymore. An Arena must not
+// be used concurrently. The zero value is an empty Arena ready to use.
+type Arena struct {
+	words   []uint64
+	values  []Value
+	fields  []objectField
+	strings []string
+}
+
+// Sizes of buffers allocated by Arena. Requests larger than a quarter
+// of a buffer get their own allocation to avoid wasting the buffer space.
+const (
+	arenaWords   = 1024
+	arenaValues  = 128
+	arenaFields  = 128
+	arenaStrings = 128
+)
+
+// SnapshotAll snapshots all values in place, see Snapshot. If arena is not
+// nil, copies of data are allocated from it and stay valid until the arena
+// is reset.
+func SnapshotAll(values []Value, arena *Arena) {
+	c := snapshotContext{arena: arena}
+	for i := range values {
+		c.snapshot(&values[i])
+	}
+}
+
+// Reset releases all the data allocated from the Arena so that its buffers
+// can be reused. Values snapshotted into the Arena must not be used after it.
+func (a *Arena) Reset() {
+	a.words = a.words[:0]
+	a.values = arenaReset(a.values)
+	a.fields = arenaReset(a.fields)
+	a.strings = arenaReset(a.strings)
+}
+
+// arenaReset zeroes the used part of the buffer so that it does not keep
+// the data it points to alive and returns it emptied.
+func arenaReset[T any](buf []T) []T {
+	var zero T
+	for i := range buf {
+		buf[i] = zero
+	}
+
+	return buf[:0]
+}
+
+// allocBytes returns a slice of n bytes aligned to 8 bytes.
+func (a *Arena) allocBytes(n int) []byte {
+	words := arenaAlloc(&a.words, (n+7)/8, arenaWords)
+
+	return unsafe.Slice((*byte)(unsafe.Pointer(&words[0])), n)
+}
+
+// allocValues returns a slice of n Values.
+func (a *Arena) allocValues(n int) []Value {
+	if a == nil {
+		return make([]Value, n)
+	}
+
+	return arenaAlloc(&a.values, n, arenaValues)
+}
+
+// allocFields returns an empty slice of object fields with capacity n.
+func (a *Arena) allocFields(n int) []objectField {
+	if a == nil {
+		return make([]objectField, 0, n)
+	}
+
+	return arenaAlloc(&a.fields, n, arenaFields)[:0]
+}
+
+// allocStrings returns a slice of n strings.
+func (a *Arena) allocStrings(n int) []string {
+	if a == nil {
+		return make([]string, n)
+	}
+
+	return arenaAlloc(&a.strings, n, arenaStrings)
+}
+
+// arenaAlloc returns n items taken from the buffer, replacing it with
+// a new buffer of the given size if there is not enough space left.
+func arenaAlloc[T any](buf *[]T, n, size int) []T {
+	if n > size/4 {
+		return make([]T, n)
+	}
+
+	b := *buf
+	if cap(b)-len(b) < n {
+		b = make([]T, 0, size)
+	}
+	*buf = b[:len(b)+n]
+
+	return b[len(b) : len(b)+n : len(b)+n]
+}
+
+// arenaCopy returns a copy of s allocated from the arena if it is not nil.
+// T must not contain pointers since the arena memory is not scanned for them.
+func arenaCopy[T any](a *Arena, s []T) []T {
+	if a == nil || len(s) == 0 {
+		cc := make([]T, len(s))
+		copy(cc, s)
+
+		return cc
+	}
+
+	b := a.allocBytes(len(s) * int(unsafe.Sizeof(s[0])))
+	cc := unsafe.Slice((*T)(unsafe.Pointer(&b[0])), len(s))
+	copy(cc, s)
+
+	return cc
+}
@@ -0,0 +1,103 @@
+package valf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testArenaRecord() ([]Value, func()) {
+	ints := []int{1, 2, 3}
+	floats := []float64{0.5}
+	bytes := []byte("bytes")
+	strings := []string{"a", "b"}
+	items := mockArray{Ints(ints), String("item")}
+	fields := mockObject{"durations": Durations([]time.Duration{time.Second})}
+
+	values := []Value{
+		Int(42),
+		Ints(ints),
+		Floats64(floats),
+		Bytes(bytes),
+		Strings(strings),
+		Array(items),
+		Object(fields),
+	}
+
+	return values, func() {
+		ints[0] = 100
+		floats[0] = 100
+		bytes[0] = 'B'
+		strings[0] = "changed"
+		items[1] = String("changed")
+		fields["durations"] = Bools([]bool{false})
+	}
+}
+
+func TestSnapshotAll(t *testing.T) {
+	golden, _ := testArenaRecord()
+	SnapshotAll(golden, nil)
+
+	var arena Arena
+	for i := 0; i != 3; i++ {
+		values, modify := testArenaRecord()
+		SnapshotAll(values, &arena)
+		modify()
+
+		for i, value := range values {
+			require.Equal(t, true, value.Const())
+			require.Equal(t, valueText(golden[i]), valueText(value))
+		}
+		arena.Reset()
+	}
+}
+
+func TestSnapshotAllLarge(t *testing.T) {
+	ints := make([]int, arenaWords)
+	ints[0] = 1
+
+	values := []Value{Ints(ints)}
+	SnapshotAll(values, &Arena{})
+	ints[0] = 2
+	require.Equal(t, ConstInts(append([]int{1}, ints[1:]...)), values[0])
+}
+
+func TestSnapshotAllAllocations(t *testing.T) {
+	values, _ := testArenaRecord()
+	snapshot := make([]Value, len(values))
+
+	withoutArena := testing.AllocsPerRun(100, func() {
+		copy(snapshot, values)
+		SnapshotAll(snapshot, nil)
+	})
+
+	var arena Arena
+	withArena := testing.AllocsPerRun(100, func() {
+		copy(snapshot, values)
+		SnapshotAll(snapshot, &arena)
+		arena.Reset()
+	})
+
+	require.True(t, withArena < withoutArena, withArena, withoutArena)
+}
+
+func BenchmarkSnapshotAll(b *testing.B) {
+	values, _ := testArenaRecord()
+	snapshot := make([]Value, len(values))
+	for i := 0; i != b.N; i++ {
+		copy(snapshot, values)
+		SnapshotAll(snapshot, nil)
+	}
+}
+
+func BenchmarkSnapshotAllArena(b *testing.B) {
+	values, _ := testArenaRecord()
+	snapshot := make([]Value, len(values))
+	var arena Arena
+	for i := 0; i != b.N; i++ {
+		copy(snapshot, values)
+		SnapshotAll(snapshot, &arena)
+		arena.Reset()
+	}
+}
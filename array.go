@@ -0,0 +1,80 @@
+package valf
+
+// Values returns a new Value with the given slice of Values presented as ValueArray.
+func Values(v []Value) Value {
+	if v == nil {
+		return ConstArray(nil)
+	}
+
+	return Array(valueArray(v))
+}
+
+// ConstValues returns a new Value with the given slice of Values presented as ValueArray.
+//
+// Call ConstValues if your slice and its values are const. It has
+// significantly less impact on the calling goroutine.
+func ConstValues(v []Value) Value {
+	if v == nil {
+		return ConstArray(nil)
+	}
+
+	return ConstArray(valueArray(v))
+}
+
+// AnySlice returns a new Value with the given slice of values of any type
+// presented as ValueArray. Items are converted using Any.
+func AnySlice(v []interface{}) Value {
+	if v == nil {
+		return ConstArray(nil)
+	}
+
+	return Array(anyArray{v, false})
+}
+
+// ConstAnySlice returns a new Value with the given slice of values of any
+// type presented as ValueArray. Items are converted using ConstAny.
+//
+// Call ConstAnySlice if your slice and its items are const. It has
+// significantly less impact on the calling goroutine.
+func ConstAnySlice(v []interface{}) Value {
+	if v == nil {
+		return ConstArray(nil)
+	}
+
+	return ConstArray(anyArray{v, true})
+}
+
+type valueArray []Value
+
+func (a valueArray) ArrayItemCount() int {
+	return len(a)
+}
+
+func (a valueArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i, item := range a {
+		visitor.VisitArrayItem(i, item)
+	}
+}
+
+func (a valueArray) StableItems() {}
+
+type anyArray struct {
+	items   []interface{}
+	isConst bool
+}
+
+func (a anyArray) ArrayItemCount() int {
+	return len(a.items)
+}
+
+func (a anyArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i, item := range a.items {
+		if a.isConst {
+			visitor.VisitArrayItem(i, ConstAny(item))
+		} else {
+			visitor.VisitArrayItem(i, Any(item))
+		}
+	}
+}
+
+func (a anyArray) StableItems() {}
@@ -0,0 +1,70 @@
+package valf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueValues(t *testing.T) {
+	v := []Value{Int(1), String("a")}
+	value := Values(v)
+	require.Equal(t, TypeArray, value.Type())
+	require.Equal(t, false, value.Const())
+	require.Equal(t, true, ConstValues(v).Const())
+
+	visitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, v, visitor.value)
+
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstValues(v), ConstAny(v))
+}
+
+func TestValueAnySlice(t *testing.T) {
+	i := 42
+	v := []interface{}{1, "a", nil, &i}
+	value := AnySlice(v)
+	require.Equal(t, TypeArray, value.Type())
+	require.Equal(t, false, value.Const())
+	require.Equal(t, true, ConstAnySlice(v).Const())
+
+	visitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []Value{Int(1), String("a"), Any(nil), Int(42)}, visitor.value)
+
+	visitor = newMockArrayVisitor(t)
+	Any(v).AcceptVisitor(visitor)
+	require.Equal(t, []Value{Int(1), String("a"), Any(nil), Int(42)}, visitor.value)
+	require.Equal(t, ConstAnySlice(v), ConstAny(v))
+}
+
+func TestValueNilValues(t *testing.T) {
+	for _, value := range []Value{Values(nil), ConstValues(nil), AnySlice(nil), ConstAnySlice(nil)} {
+		visitor := newMockArrayVisitor(t)
+		value.AcceptVisitor(visitor)
+		require.Equal(t, true, visitor.visited)
+		require.Equal(t, []Value(nil), visitor.value)
+	}
+}
+
+func TestValuesSnapshot(t *testing.T) {
+	ints := []int{1, 2}
+	v := []Value{Ints(ints), String("a")}
+	value := Values(v).Snapshot()
+	ints[0] = 42
+	v[1] = String("b")
+	require.Equal(t, true, value.Const())
+
+	visitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []Value{ConstInts([]int{1, 2}), String("a")}, visitor.value)
+
+	items := []interface{}{[]int{1}}
+	value = AnySlice(items).Snapshot()
+	items[0] = "changed"
+	visitor = newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []Value{ConstInts([]int{1})}, visitor.value)
+}
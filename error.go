@@ -0,0 +1,111 @@
+package valf
+
+import (
+	"reflect"
+)
+
+// Field names used by ErrorDetails.
+const (
+	ErrorFieldMessage = "message"
+	ErrorFieldType    = "type"
+	ErrorFieldCauses  = "causes"
+	ErrorFieldFields  = "fields"
+)
+
+// ErrorDetails returns a new Value with an object describing the given
+// error. The object contains the following fields:
+//   - "message" with the error message;
+//   - "type" with the Go type of the error;
+//   - "causes" with an array of objects describing errors returned by
+//     Unwrap() error or Unwrap() []error, omitted if there are none;
+//   - "fields" with the error itself if it implements ValueObject,
+//     omitted otherwise.
+//
+// The object is built lazily while being visited. Snapshot of an Error value
+// captures all the data needed to build it, so ErrorDetails can be used with
+// errors taken from snapshotted values as well.
+func ErrorDetails(err error) Value {
+	if err == nil {
+		return ConstObject(nil)
+	}
+
+	return Object(errorObject{err})
+}
+
+type errorObject struct {
+	err error
+}
+
+func (o errorObject) ObjectFieldCount() int {
+	n := 2
+	if len(unwrapError(o.err)) != 0 {
+		n++
+	}
+	if o.fields() != nil {
+		n++
+	}
+
+	return n
+}
+
+func (o errorObject) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	visitor.VisitObjectField(ErrorFieldMessage, String(o.err.Error()))
+	visitor.VisitObjectField(ErrorFieldType, String(errorTypeName(o.err)))
+	if causes := unwrapError(o.err); len(causes) != 0 {
+		visitor.VisitObjectField(ErrorFieldCauses, Array(errorCauses(causes)))
+	}
+	if fields := o.fields(); fields != nil {
+		visitor.VisitObjectField(ErrorFieldFields, Object(fields))
+	}
+}
+
+func (o errorObject) fields() ValueObject {
+	if s, ok := o.err.(*errorSnapshot); ok {
+		return s.fields
+	}
+	if fields, ok := o.err.(ValueObject); ok {
+		return fields
+	}
+
+	return nil
+}
+
+type errorCauses []error
+
+func (a errorCauses) ArrayItemCount() int {
+	return len(a)
+}
+
+func (a errorCauses) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i, err := range a {
+		visitor.VisitArrayItem(i, ErrorDetails(err))
+	}
+}
+
+// unwrapError returns errors directly wrapped by err.
+func unwrapError(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	}
+
+	return nil
+}
+
+// textError returns an error with the given message and the type name of
+// err but without its causes and fields.
+func textError(err error, message string) error {
+	return &errorSnapshot{message: message, typeName: errorTypeName(err)}
+}
+
+func errorTypeName(err error) string {
+	if s, ok := err.(*errorSnapshot); ok {
+		return s.typeName
+	}
+
+	return reflect.TypeOf(err).String()
+}
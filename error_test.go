@@ -0,0 +1,258 @@
+package valf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testFieldsError struct {
+	mockObject
+}
+
+func (e testFieldsError) Error() string {
+	return "fields error"
+}
+
+type testMultiError []error
+
+func (e testMultiError) Error() string {
+	return "multi error"
+}
+
+func (e testMultiError) Unwrap() []error {
+	return e
+}
+
+func errorDetailsFields(t *testing.T, v Value) map[string]Value {
+	visitor := newMockObjectVisitor(t)
+	v.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, len(visitor.value), visitor.count)
+
+	return visitor.value
+}
+
+func errorDetailsCauses(t *testing.T, v Value) []Value {
+	visitor := newMockArrayVisitor(t)
+	v.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+
+	return visitor.value
+}
+
+func testErrorDetails(t *testing.T, err error) {
+	fields := errorDetailsFields(t, ErrorDetails(err))
+	require.Equal(t, 3, len(fields))
+	require.Equal(t, String("wrapped: multi error"), fields[ErrorFieldMessage])
+	require.Equal(t, String("*fmt.wrapError"), fields[ErrorFieldType])
+
+	causes := errorDetailsCauses(t, fields[ErrorFieldCauses])
+	require.Equal(t, 1, len(causes))
+
+	multi := errorDetailsFields(t, causes[0])
+	require.Equal(t, String("multi error"), multi[ErrorFieldMessage])
+	require.Equal(t, String("valf.testMultiError"), multi[ErrorFieldType])
+
+	causes = errorDetailsCauses(t, multi[ErrorFieldCauses])
+	require.Equal(t, 2, len(causes))
+
+	first := errorDetailsFields(t, causes[0])
+	require.Equal(t, 2, len(first))
+	require.Equal(t, String("first"), first[ErrorFieldMessage])
+	require.Equal(t, String("*errors.errorString"), first[ErrorFieldType])
+
+	second := errorDetailsFields(t, causes[1])
+	require.Equal(t, 3, len(second))
+	require.Equal(t, String("fields error"), second[ErrorFieldMessage])
+	require.Equal(t, String("valf.testFieldsError"), second[ErrorFieldType])
+	require.Equal(t, map[string]Value{"code": Int(42)}, errorDetailsFields(t, second[ErrorFieldFields]))
+}
+
+func TestErrorDetails(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", testMultiError{
+		errors.New("first"),
+		testFieldsError{mockObject{"code": Int(42)}},
+	})
+
+	testErrorDetails(t, err)
+}
+
+func TestErrorDetailsSnapshot(t *testing.T) {
+	fields := mockObject{"code": Int(42)}
+	err := fmt.Errorf("wrapped: %w", testMultiError{
+		errors.New("first"),
+		testFieldsError{fields},
+	})
+
+	value := Error(err).Snapshot()
+	require.Equal(t, true, value.Const())
+	fields["code"] = Int(21)
+
+	visitor := newMockErrorVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, "wrapped: multi error", visitor.value.Error())
+	testErrorDetails(t, visitor.value)
+}
+
+func TestErrorDetailsNil(t *testing.T) {
+	require.Equal(t, ConstObject(nil), ErrorDetails(nil))
+}
+
+type testSnapshotterError struct {
+	state *string
+}
+
+func (e testSnapshotterError) Error() string {
+	return "state: " + *e.state
+}
+
+func (e testSnapshotterError) TakeSnapshot() interface{} {
+	state := *e.state
+
+	return testSnapshotterError{&state}
+}
+
+func TestErrorSnapshotter(t *testing.T) {
+	state := "initial"
+	err := fmt.Errorf("wrapped: %w", testSnapshotterError{&state})
+
+	value := Error(testSnapshotterError{&state}).Snapshot()
+	wrapped := Error(err).Snapshot()
+	state = "changed"
+
+	visitor := newMockErrorVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, "state: initial", visitor.value.Error())
+
+	var target testSnapshotterError
+	require.True(t, errors.As(visitor.value, &target))
+
+	visitor = newMockErrorVisitor(t)
+	wrapped.AcceptVisitor(visitor)
+	require.True(t, errors.As(visitor.value, &target))
+	require.Equal(t, "state: initial", target.Error())
+}
+
+func TestErrorSnapshotConcurrentModification(t *testing.T) {
+	err := &testMutableError{"initial"}
+	value := Error(fmt.Errorf("wrapped: %w", err)).Snapshot()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i != 100; i++ {
+			err.message = fmt.Sprint(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i != 100; i++ {
+			visitor := newMockErrorVisitor(t)
+			value.AcceptVisitor(visitor)
+			if visitor.value.Error() != "wrapped: initial" {
+				t.Errorf("unexpected message: %s", visitor.value.Error())
+			}
+			ErrorDetails(visitor.value).AcceptVisitor(IgnoringVisitor{})
+		}
+	}()
+	wg.Wait()
+}
+
+type testValueError struct {
+	code int
+}
+
+func (e testValueError) Error() string {
+	return fmt.Sprint("code ", e.code)
+}
+
+func TestErrorSnapshotIsAs(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	mutable := &testMutableError{"mutable"}
+	err := fmt.Errorf("wrapped: %w", testMultiError{sentinel, testValueError{42}, mutable})
+
+	visitor := newMockErrorVisitor(t)
+	Error(err).Snapshot().AcceptVisitor(visitor)
+	snapshot := visitor.value
+	require.Equal(t, "wrapped: multi error", snapshot.Error())
+
+	require.True(t, errors.Is(snapshot, sentinel))
+	require.True(t, errors.Is(snapshot, mutable))
+	require.True(t, errors.Is(snapshot, testValueError{42}))
+	require.False(t, errors.Is(snapshot, errors.New("sentinel")))
+
+	var valueErr testValueError
+	require.True(t, errors.As(snapshot, &valueErr))
+	require.Equal(t, 42, valueErr.code)
+
+	// Pointer errors may be modified after snapshot, so they are not exposed.
+	var mutableErr *testMutableError
+	require.False(t, errors.As(snapshot, &mutableErr))
+}
+
+type testSharingError struct {
+	state *string
+}
+
+func (e testSharingError) Error() string {
+	return *e.state
+}
+
+func TestErrorSnapshotAsSharing(t *testing.T) {
+	state := "initial"
+	err := testSharingError{&state}
+
+	visitor := newMockErrorVisitor(t)
+	Error(err).Snapshot().AcceptVisitor(visitor)
+	require.True(t, errors.Is(visitor.value, err))
+
+	// Copies of the error share its state, so they are not exposed.
+	var target testSharingError
+	require.False(t, errors.As(visitor.value, &target))
+}
+
+func TestErrorSnapshotSize(t *testing.T) {
+	small := Error(testValueError{1}).Snapshot()
+	large := Error(&testMutableError{strings.Repeat("a", 100)}).Snapshot()
+	require.True(t, SizeOf(large)-SizeOf(small) >= 200, "the message and the original error are counted")
+}
+
+func TestIsPlainType(t *testing.T) {
+	type plain struct {
+		a int
+		b string
+		c [2]float64
+		d struct{ e bool }
+	}
+	type indirect struct {
+		a int
+		b []int
+	}
+
+	require.True(t, isPlainType(reflect.TypeOf(plain{})))
+	require.True(t, isPlainType(reflect.TypeOf(testValueError{})))
+	require.False(t, isPlainType(reflect.TypeOf(indirect{})))
+	require.False(t, isPlainType(reflect.TypeOf(testSharingError{})))
+	require.False(t, isPlainType(reflect.TypeOf(&testMutableError{})))
+	require.False(t, isPlainType(reflect.TypeOf([1]map[int]int{})))
+	require.False(t, isPlainType(reflect.TypeOf(struct{ e error }{})))
+}
+
+func TestErrorSnapshotNotComparable(t *testing.T) {
+	err := testMultiError{errors.New("first")}
+
+	visitor := newMockErrorVisitor(t)
+	Error(err).Snapshot().AcceptVisitor(visitor)
+	require.Equal(t, "multi error", visitor.value.Error())
+	require.False(t, errors.Is(visitor.value, errors.New("first")))
+
+	var target testMultiError
+	require.False(t, errors.As(visitor.value, &target))
+}
@@ -0,0 +1,226 @@
+package valf
+
+import "sync/atomic"
+
+// Field is a key-value pair of Fields.
+type Field struct {
+	Key   string
+	Value Value
+}
+
+// Fields is an ordered list of key-value pairs, e.g. a log record.
+// It implements ValueObject, so it can be passed to Object. If there are
+// several fields with the same key, the last one overrides the previous ones.
+// Overridden fields are tracked when fields are added, so getting and
+// visiting fields do not allocate memory.
+//
+// The zero value is an empty Fields ready to use. Fields must not be copied
+// by assignment if any of the copies is modified afterwards, use Clone instead.
+// Fields may be read and cloned concurrently.
+type Fields struct {
+	items      []Field
+	index      map[string]int
+	overridden int
+	storage    *fieldsStorage
+}
+
+// fieldsStorage is allocated along with the items and the index which
+// Fields may modify in place. Fields without it, e.g. clones, copy
+// the storage before modifying it.
+type fieldsStorage struct {
+	// cloned is set by Clone, so that the owner copies the storage
+	// shared with clones before modifying it.
+	cloned atomic.Bool
+}
+
+// MakeFields returns a new empty Fields with the given capacity.
+func MakeFields(capacity int) Fields {
+	return Fields{items: make([]Field, 0, capacity), storage: &fieldsStorage{}}
+}
+
+// FieldsOf returns a new Fields with the given fields.
+// The slice is used directly, so it must not be modified afterwards.
+func FieldsOf(fields ...Field) Fields {
+	f := Fields{items: fields[:len(fields):len(fields)]}
+	if len(f.items) > maxScanFields {
+		f.buildIndex()
+	}
+	for i := range f.items {
+		if f.isOverridden(i) {
+			f.overridden++
+		}
+	}
+
+	return f
+}
+
+// Len returns the number of fields including overridden ones.
+func (f Fields) Len() int {
+	return len(f.items)
+}
+
+// Items returns the underlying slice of fields including overridden ones.
+// The slice must not be modified.
+func (f Fields) Items() []Field {
+	return f.items
+}
+
+// Get returns the value of the last field with the given key.
+func (f Fields) Get(key string) (Value, bool) {
+	if i := f.lookup(key); i >= 0 {
+		return f.items[i].Value, true
+	}
+
+	return Value{}, false
+}
+
+// Add appends a field with the given key and value.
+// It overrides any previously added fields with the same key.
+func (f *Fields) Add(key string, value Value) {
+	if !f.owned() {
+		f.unshare()
+	}
+	if f.lookup(key) >= 0 {
+		f.overridden++
+	}
+
+	f.items = append(f.items, Field{key, value})
+	switch {
+	case f.index != nil:
+		f.index[key] = len(f.items) - 1
+	case len(f.items) > maxScanFields:
+		f.buildIndex()
+	}
+}
+
+// Set replaces the value of the last field with the given key or appends
+// a new field if there is no such field. Unlike Add, it does not grow Fields
+// when the key is already present.
+func (f *Fields) Set(key string, value Value) {
+	i := f.lookup(key)
+	if i < 0 {
+		f.Add(key, value)
+
+		return
+	}
+
+	if !f.owned() {
+		f.unshare()
+	}
+	f.items[i].Value = value
+}
+
+// Clone returns a copy of f which shares the underlying storage with f until
+// either of them is modified. It does not modify f, so several goroutines
+// may clone the same Fields concurrently.
+func (f Fields) Clone() Fields {
+	if f.storage != nil && !f.storage.cloned.Load() {
+		f.storage.cloned.Store(true)
+	}
+
+	n := len(f.items)
+
+	return Fields{items: f.items[:n:n], index: f.index, overridden: f.overridden}
+}
+
+// Snapshot returns a copy of f with all values snapshotted, see Value.Snapshot.
+func (f Fields) Snapshot() Fields {
+	items := make([]Field, len(f.items))
+	for i, field := range f.items {
+		Snapshot(&field.Value)
+		items[i] = field
+	}
+
+	return Fields{items: items, index: copyIndex(f.index), overridden: f.overridden}
+}
+
+// ObjectFieldCount returns the number of distinct keys.
+func (f Fields) ObjectFieldCount() int {
+	return len(f.items) - f.overridden
+}
+
+// AcceptObjectFieldVisitor visits fields in the order they were added
+// skipping fields overridden by later ones.
+func (f Fields) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	for i, field := range f.items {
+		if f.overridden == 0 || !f.isOverridden(i) {
+			visitor.VisitObjectField(field.Key, field.Value)
+		}
+	}
+}
+
+// StableItems implements StableItems, so large Fields may be snapshotted
+// in parallel.
+func (f Fields) StableItems() {}
+
+// maxScanFields is the maximum number of fields for which fields with
+// the same key are found by scanning instead of using an index.
+const maxScanFields = 16
+
+// lookup returns the position of the last field with the given key or -1.
+func (f Fields) lookup(key string) int {
+	if f.index != nil {
+		if i, ok := f.index[key]; ok {
+			return i
+		}
+
+		return -1
+	}
+
+	for i := len(f.items) - 1; i >= 0; i-- {
+		if f.items[i].Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (f Fields) isOverridden(i int) bool {
+	if f.index != nil {
+		return f.index[f.items[i].Key] != i
+	}
+
+	for _, field := range f.items[i+1:] {
+		if field.Key == f.items[i].Key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildIndex maps keys to positions of the last fields with them.
+func (f *Fields) buildIndex() {
+	f.index = make(map[string]int, cap(f.items))
+	for i, field := range f.items {
+		f.index[field.Key] = i
+	}
+}
+
+// owned reports whether f may modify its storage in place.
+func (f *Fields) owned() bool {
+	return f.storage != nil && !f.storage.cloned.Load()
+}
+
+// unshare copies the storage which f does not own before it is modified.
+func (f *Fields) unshare() {
+	items := make([]Field, len(f.items), 2*len(f.items)+1)
+	copy(items, f.items)
+	f.items = items
+	f.index = copyIndex(f.index)
+	f.storage = &fieldsStorage{}
+}
+
+func copyIndex(index map[string]int) map[string]int {
+	if index == nil {
+		return nil
+	}
+
+	cc := make(map[string]int, len(index))
+	for key, i := range index {
+		cc[key] = i
+	}
+
+	return cc
+}
@@ -0,0 +1,166 @@
+package valf
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields(t *testing.T) {
+	var f Fields
+	f.Add("a", Int(1))
+	f.Add("b", Int(2))
+	f.Add("a", Int(3))
+	require.Equal(t, 3, f.Len())
+
+	v, ok := f.Get("a")
+	require.Equal(t, true, ok)
+	require.Equal(t, Int(3), v)
+	_, ok = f.Get("c")
+	require.Equal(t, false, ok)
+
+	visitor := newOrderedObjectVisitor(t)
+	Object(f).AcceptVisitor(visitor)
+	require.Equal(t, 2, visitor.count)
+	require.Equal(t, []string{"b", "a"}, visitor.keys)
+	require.Equal(t, Int(3), visitor.value["a"])
+
+	f.Set("b", Int(4))
+	f.Set("c", Int(5))
+	require.Equal(t, 4, f.Len())
+	require.Equal(t, []Field{{"a", Int(1)}, {"b", Int(4)}, {"a", Int(3)}, {"c", Int(5)}}, f.Items())
+}
+
+func TestFieldsMany(t *testing.T) {
+	f := MakeFields(maxScanFields * 2)
+	for i := 0; i != maxScanFields*2; i++ {
+		f.Add(fmt.Sprint(i%maxScanFields), Int(i))
+	}
+	require.Equal(t, maxScanFields, f.ObjectFieldCount())
+
+	visitor := newOrderedObjectVisitor(t)
+	Object(f).AcceptVisitor(visitor)
+	require.Equal(t, "0", visitor.keys[0])
+	require.Equal(t, Int(maxScanFields), visitor.value["0"])
+}
+
+func TestFieldsClone(t *testing.T) {
+	f := FieldsOf(Field{"a", Int(1)}, Field{"b", Int(2)})
+	c := f.Clone()
+	c.Set("a", Int(10))
+	c.Add("c", Int(3))
+	f.Add("d", Int(4))
+
+	require.Equal(t, []Field{{"a", Int(1)}, {"b", Int(2)}, {"d", Int(4)}}, f.Items())
+	require.Equal(t, []Field{{"a", Int(10)}, {"b", Int(2)}, {"c", Int(3)}}, c.Items())
+
+	f.Set("a", Int(5))
+	require.Equal(t, []Field{{"a", Int(10)}, {"b", Int(2)}, {"c", Int(3)}}, c.Items())
+}
+
+func TestFieldsSnapshot(t *testing.T) {
+	v := []int{1, 2}
+	var f Fields
+	f.Add("ints", Ints(v))
+	s := f.Snapshot()
+	v[0] = 42
+
+	value, _ := s.Get("ints")
+	require.Equal(t, true, value.Const())
+	require.Equal(t, ConstInts([]int{1, 2}), value)
+}
+
+func TestFieldsOfDuplicates(t *testing.T) {
+	for _, n := range []int{4, maxScanFields * 2} {
+		items := make([]Field, n)
+		for i := range items {
+			items[i] = Field{fmt.Sprint(i % (n / 2)), Int(i)}
+		}
+
+		f := FieldsOf(items...)
+		require.Equal(t, n/2, f.ObjectFieldCount())
+		v, ok := f.Get("0")
+		require.Equal(t, true, ok)
+		require.Equal(t, Int(n/2), v)
+	}
+}
+
+func TestFieldsCloneIndexed(t *testing.T) {
+	var f Fields
+	for i := 0; i != maxScanFields+1; i++ {
+		f.Add(fmt.Sprint(i), Int(i))
+	}
+
+	c := f.Clone()
+	c.Add("0", Int(100))
+	f.Add("new", Int(200))
+
+	v, _ := f.Get("0")
+	require.Equal(t, Int(0), v)
+	_, ok := c.Get("new")
+	require.Equal(t, false, ok)
+	require.Equal(t, maxScanFields+2, f.ObjectFieldCount())
+	require.Equal(t, maxScanFields+1, c.ObjectFieldCount())
+
+	s := c.Snapshot()
+	c.Add("1", Int(101))
+	v, _ = s.Get("1")
+	require.Equal(t, Int(1), v)
+	require.Equal(t, maxScanFields+1, s.ObjectFieldCount())
+}
+
+func TestFieldsCloneConcurrent(t *testing.T) {
+	for _, n := range []int{2, maxScanFields + 1} {
+		base := MakeFields(n + 1)
+		for i := 0; i != n; i++ {
+			base.Add(fmt.Sprint(i), Int(i))
+		}
+
+		var wg sync.WaitGroup
+		for g := 0; g != 4; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i != 100; i++ {
+					c := base.Clone()
+					c.Add("g", Int(g))
+					c.Set("0", Int(g))
+					v, _ := c.Get("0")
+					require.Equal(t, Int(g), v)
+					v, _ = base.Get("0")
+					require.Equal(t, Int(0), v)
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		c := base.Clone()
+		base.Set("0", Int(100))
+		base.Add("new", Int(200))
+		v, _ := c.Get("0")
+		require.Equal(t, Int(0), v)
+		_, ok := c.Get("new")
+		require.Equal(t, false, ok)
+	}
+}
+
+func TestFieldsVisitAllocs(t *testing.T) {
+	for _, n := range []int{maxScanFields, maxScanFields * 4} {
+		f := MakeFields(n)
+		for i := 0; i != n; i++ {
+			f.Add(fmt.Sprint(i%(n/2)), Int(i))
+		}
+
+		require.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+			_ = f.ObjectFieldCount()
+			_, _ = f.Get("1")
+			f.AcceptObjectFieldVisitor(ignoringFieldVisitor{})
+		}))
+	}
+}
+
+type ignoringFieldVisitor struct{}
+
+func (ignoringFieldVisitor) VisitObjectField(string, Value) {}
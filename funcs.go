@@ -0,0 +1,643 @@
+package valf
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"time"
+)
+
+// VisitorFuncs is an implementation of Visitor interface and all its optional
+// extensions built from plain functions. Each value is passed to the function
+// specific to its type if it is set, otherwise to Fallback. If neither is
+// set, the value is ignored and the error is recorded in Err, unless Strict
+// is true, in which case VisitorFuncs panics.
+//
+// Values of extended types are never passed to the functions used by
+// visitors without the extension, e.g. IP addresses are not passed to
+// String. The only exception is annotated values, which are visited as if
+// they had no annotations unless Annotated is set.
+type VisitorFuncs struct {
+	None      func()
+	Any       func(interface{})
+	Bool      func(bool)
+	Int       func(int)
+	Int8      func(int8)
+	Int16     func(int16)
+	Int32     func(int32)
+	Int64     func(int64)
+	Uint      func(uint)
+	Uint8     func(uint8)
+	Uint16    func(uint16)
+	Uint32    func(uint32)
+	Uint64    func(uint64)
+	Float32   func(float32)
+	Float64   func(float64)
+	Duration  func(time.Duration)
+	Error     func(error)
+	Time      func(time.Time)
+	String    func(string)
+	Strings   func([]string)
+	Bytes     func([]byte)
+	Bools     func([]bool)
+	Ints      func([]int)
+	Ints8     func([]int8)
+	Ints16    func([]int16)
+	Ints32    func([]int32)
+	Ints64    func([]int64)
+	Uints     func([]uint)
+	Uints8    func([]uint8)
+	Uints16   func([]uint16)
+	Uints32   func([]uint32)
+	Uints64   func([]uint64)
+	Floats32  func([]float32)
+	Floats64  func([]float64)
+	Durations func([]time.Duration)
+	Array     func(ValueArray)
+	Object    func(ValueObject)
+
+	BigInt       func(*big.Int)
+	BigFloat     func(*big.Float)
+	BigRat       func(*big.Rat)
+	Decimal      func(coefficient *big.Int, exponent int32)
+	Complex64    func(complex64)
+	Complex128   func(complex128)
+	Complexes64  func([]complex64)
+	Complexes128 func([]complex128)
+	Uintptr      func(uintptr)
+	Uintptrs     func([]uintptr)
+	IP           func(netip.Addr)
+	IPPort       func(netip.AddrPort)
+	IPPrefix     func(netip.Prefix)
+	MAC          func(net.HardwareAddr)
+	UUID         func([16]byte)
+	Raw          func(Encoding, []byte)
+	Enum         func(value int64, name string)
+	Annotated    func(Value, []Annotation)
+	Null         func()
+	Times        func([]time.Time)
+	Errors       func([]error)
+	Stringers    func([]fmt.Stringer)
+
+	// Fallback is called with values which have no type-specific function.
+	Fallback func(Value)
+
+	// Strict makes VisitorFuncs panic on values which have neither
+	// type-specific function nor Fallback.
+	Strict bool
+
+	// Err is the error describing the first value which had neither
+	// type-specific function nor Fallback. It is never reset by VisitorFuncs.
+	Err error
+}
+
+// VisitNone calls f.None.
+func (f *VisitorFuncs) VisitNone() {
+	if f.None != nil {
+		f.None()
+	} else {
+		f.fallback(Value{})
+	}
+}
+
+// VisitAny calls f.Any.
+func (f *VisitorFuncs) VisitAny(v interface{}) {
+	if f.Any != nil {
+		f.Any(v)
+	} else if v == nil {
+		f.fallback(Value{bits: bits(TypeAny) | bitsConst})
+	} else {
+		f.fallback(Value{bits: bits(TypeAny), vAny: v})
+	}
+}
+
+// VisitBool calls f.Bool.
+func (f *VisitorFuncs) VisitBool(v bool) {
+	if f.Bool != nil {
+		f.Bool(v)
+	} else {
+		f.fallback(Bool(v))
+	}
+}
+
+// VisitInt calls f.Int.
+func (f *VisitorFuncs) VisitInt(v int) {
+	if f.Int != nil {
+		f.Int(v)
+	} else {
+		f.fallback(Int(v))
+	}
+}
+
+// VisitInt8 calls f.Int8.
+func (f *VisitorFuncs) VisitInt8(v int8) {
+	if f.Int8 != nil {
+		f.Int8(v)
+	} else {
+		f.fallback(Int8(v))
+	}
+}
+
+// VisitInt16 calls f.Int16.
+func (f *VisitorFuncs) VisitInt16(v int16) {
+	if f.Int16 != nil {
+		f.Int16(v)
+	} else {
+		f.fallback(Int16(v))
+	}
+}
+
+// VisitInt32 calls f.Int32.
+func (f *VisitorFuncs) VisitInt32(v int32) {
+	if f.Int32 != nil {
+		f.Int32(v)
+	} else {
+		f.fallback(Int32(v))
+	}
+}
+
+// VisitInt64 calls f.Int64.
+func (f *VisitorFuncs) VisitInt64(v int64) {
+	if f.Int64 != nil {
+		f.Int64(v)
+	} else {
+		f.fallback(Int64(v))
+	}
+}
+
+// VisitUint calls f.Uint.
+func (f *VisitorFuncs) VisitUint(v uint) {
+	if f.Uint != nil {
+		f.Uint(v)
+	} else {
+		f.fallback(Uint(v))
+	}
+}
+
+// VisitUint8 calls f.Uint8.
+func (f *VisitorFuncs) VisitUint8(v uint8) {
+	if f.Uint8 != nil {
+		f.Uint8(v)
+	} else {
+		f.fallback(Uint8(v))
+	}
+}
+
+// VisitUint16 calls f.Uint16.
+func (f *VisitorFuncs) VisitUint16(v uint16) {
+	if f.Uint16 != nil {
+		f.Uint16(v)
+	} else {
+		f.fallback(Uint16(v))
+	}
+}
+
+// VisitUint32 calls f.Uint32.
+func (f *VisitorFuncs) VisitUint32(v uint32) {
+	if f.Uint32 != nil {
+		f.Uint32(v)
+	} else {
+		f.fallback(Uint32(v))
+	}
+}
+
+// VisitUint64 calls f.Uint64.
+func (f *VisitorFuncs) VisitUint64(v uint64) {
+	if f.Uint64 != nil {
+		f.Uint64(v)
+	} else {
+		f.fallback(Uint64(v))
+	}
+}
+
+// VisitFloat32 calls f.Float32.
+func (f *VisitorFuncs) VisitFloat32(v float32) {
+	if f.Float32 != nil {
+		f.Float32(v)
+	} else {
+		f.fallback(Float32(v))
+	}
+}
+
+// VisitFloat64 calls f.Float64.
+func (f *VisitorFuncs) VisitFloat64(v float64) {
+	if f.Float64 != nil {
+		f.Float64(v)
+	} else {
+		f.fallback(Float64(v))
+	}
+}
+
+// VisitDuration calls f.Duration.
+func (f *VisitorFuncs) VisitDuration(v time.Duration) {
+	if f.Duration != nil {
+		f.Duration(v)
+	} else {
+		f.fallback(Duration(v))
+	}
+}
+
+// VisitError calls f.Error.
+func (f *VisitorFuncs) VisitError(v error) {
+	if f.Error != nil {
+		f.Error(v)
+	} else {
+		f.fallback(Error(v))
+	}
+}
+
+// VisitTime calls f.Time.
+func (f *VisitorFuncs) VisitTime(v time.Time) {
+	if f.Time != nil {
+		f.Time(v)
+	} else {
+		f.fallback(Time(v))
+	}
+}
+
+// VisitString calls f.String.
+func (f *VisitorFuncs) VisitString(v string) {
+	if f.String != nil {
+		f.String(v)
+	} else {
+		f.fallback(String(v))
+	}
+}
+
+// VisitStrings calls f.Strings.
+func (f *VisitorFuncs) VisitStrings(v []string) {
+	if f.Strings != nil {
+		f.Strings(v)
+	} else {
+		f.fallback(Strings(v))
+	}
+}
+
+// VisitBytes calls f.Bytes.
+func (f *VisitorFuncs) VisitBytes(v []byte) {
+	if f.Bytes != nil {
+		f.Bytes(v)
+	} else {
+		f.fallback(Bytes(v))
+	}
+}
+
+// VisitBools calls f.Bools.
+func (f *VisitorFuncs) VisitBools(v []bool) {
+	if f.Bools != nil {
+		f.Bools(v)
+	} else {
+		f.fallback(Bools(v))
+	}
+}
+
+// VisitInts calls f.Ints.
+func (f *VisitorFuncs) VisitInts(v []int) {
+	if f.Ints != nil {
+		f.Ints(v)
+	} else {
+		f.fallback(Ints(v))
+	}
+}
+
+// VisitInts8 calls f.Ints8.
+func (f *VisitorFuncs) VisitInts8(v []int8) {
+	if f.Ints8 != nil {
+		f.Ints8(v)
+	} else {
+		f.fallback(Ints8(v))
+	}
+}
+
+// VisitInts16 calls f.Ints16.
+func (f *VisitorFuncs) VisitInts16(v []int16) {
+	if f.Ints16 != nil {
+		f.Ints16(v)
+	} else {
+		f.fallback(Ints16(v))
+	}
+}
+
+// VisitInts32 calls f.Ints32.
+func (f *VisitorFuncs) VisitInts32(v []int32) {
+	if f.Ints32 != nil {
+		f.Ints32(v)
+	} else {
+		f.fallback(Ints32(v))
+	}
+}
+
+// VisitInts64 calls f.Ints64.
+func (f *VisitorFuncs) VisitInts64(v []int64) {
+	if f.Ints64 != nil {
+		f.Ints64(v)
+	} else {
+		f.fallback(Ints64(v))
+	}
+}
+
+// VisitUints calls f.Uints.
+func (f *VisitorFuncs) VisitUints(v []uint) {
+	if f.Uints != nil {
+		f.Uints(v)
+	} else {
+		f.fallback(Uints(v))
+	}
+}
+
+// VisitUints8 calls f.Uints8.
+func (f *VisitorFuncs) VisitUints8(v []uint8) {
+	if f.Uints8 != nil {
+		f.Uints8(v)
+	} else {
+		f.fallback(Uints8(v))
+	}
+}
+
+// VisitUints16 calls f.Uints16.
+func (f *VisitorFuncs) VisitUints16(v []uint16) {
+	if f.Uints16 != nil {
+		f.Uints16(v)
+	} else {
+		f.fallback(Uints16(v))
+	}
+}
+
+// VisitUints32 calls f.Uints32.
+func (f *VisitorFuncs) VisitUints32(v []uint32) {
+	if f.Uints32 != nil {
+		f.Uints32(v)
+	} else {
+		f.fallback(Uints32(v))
+	}
+}
+
+// VisitUints64 calls f.Uints64.
+func (f *VisitorFuncs) VisitUints64(v []uint64) {
+	if f.Uints64 != nil {
+		f.Uints64(v)
+	} else {
+		f.fallback(Uints64(v))
+	}
+}
+
+// VisitFloats32 calls f.Floats32.
+func (f *VisitorFuncs) VisitFloats32(v []float32) {
+	if f.Floats32 != nil {
+		f.Floats32(v)
+	} else {
+		f.fallback(Floats32(v))
+	}
+}
+
+// VisitFloats64 calls f.Floats64.
+func (f *VisitorFuncs) VisitFloats64(v []float64) {
+	if f.Floats64 != nil {
+		f.Floats64(v)
+	} else {
+		f.fallback(Floats64(v))
+	}
+}
+
+// VisitDurations calls f.Durations.
+func (f *VisitorFuncs) VisitDurations(v []time.Duration) {
+	if f.Durations != nil {
+		f.Durations(v)
+	} else {
+		f.fallback(Durations(v))
+	}
+}
+
+// VisitArray calls f.Array.
+func (f *VisitorFuncs) VisitArray(v ValueArray) {
+	if f.Array != nil {
+		f.Array(v)
+	} else {
+		f.fallback(Array(v))
+	}
+}
+
+// VisitObject calls f.Object.
+func (f *VisitorFuncs) VisitObject(v ValueObject) {
+	if f.Object != nil {
+		f.Object(v)
+	} else {
+		f.fallback(Object(v))
+	}
+}
+
+// VisitBigInt calls f.BigInt.
+func (f *VisitorFuncs) VisitBigInt(v *big.Int) {
+	if f.BigInt != nil {
+		f.BigInt(v)
+	} else {
+		f.fallback(BigInt(v))
+	}
+}
+
+// VisitBigFloat calls f.BigFloat.
+func (f *VisitorFuncs) VisitBigFloat(v *big.Float) {
+	if f.BigFloat != nil {
+		f.BigFloat(v)
+	} else {
+		f.fallback(BigFloat(v))
+	}
+}
+
+// VisitBigRat calls f.BigRat.
+func (f *VisitorFuncs) VisitBigRat(v *big.Rat) {
+	if f.BigRat != nil {
+		f.BigRat(v)
+	} else {
+		f.fallback(BigRat(v))
+	}
+}
+
+// VisitDecimal calls f.Decimal.
+func (f *VisitorFuncs) VisitDecimal(coefficient *big.Int, exponent int32) {
+	if f.Decimal != nil {
+		f.Decimal(coefficient, exponent)
+	} else {
+		f.fallback(Decimal(coefficient, exponent))
+	}
+}
+
+// VisitComplex64 calls f.Complex64.
+func (f *VisitorFuncs) VisitComplex64(v complex64) {
+	if f.Complex64 != nil {
+		f.Complex64(v)
+	} else {
+		f.fallback(Complex64(v))
+	}
+}
+
+// VisitComplex128 calls f.Complex128.
+func (f *VisitorFuncs) VisitComplex128(v complex128) {
+	if f.Complex128 != nil {
+		f.Complex128(v)
+	} else {
+		f.fallback(Complex128(v))
+	}
+}
+
+// VisitComplexes64 calls f.Complexes64.
+func (f *VisitorFuncs) VisitComplexes64(v []complex64) {
+	if f.Complexes64 != nil {
+		f.Complexes64(v)
+	} else {
+		f.fallback(Complexes64(v))
+	}
+}
+
+// VisitComplexes128 calls f.Complexes128.
+func (f *VisitorFuncs) VisitComplexes128(v []complex128) {
+	if f.Complexes128 != nil {
+		f.Complexes128(v)
+	} else {
+		f.fallback(Complexes128(v))
+	}
+}
+
+// VisitUintptr calls f.Uintptr.
+func (f *VisitorFuncs) VisitUintptr(v uintptr) {
+	if f.Uintptr != nil {
+		f.Uintptr(v)
+	} else {
+		f.fallback(Uintptr(v))
+	}
+}
+
+// VisitUintptrs calls f.Uintptrs.
+func (f *VisitorFuncs) VisitUintptrs(v []uintptr) {
+	if f.Uintptrs != nil {
+		f.Uintptrs(v)
+	} else {
+		f.fallback(Uintptrs(v))
+	}
+}
+
+// VisitIP calls f.IP.
+func (f *VisitorFuncs) VisitIP(v netip.Addr) {
+	if f.IP != nil {
+		f.IP(v)
+	} else {
+		f.fallback(IP(v))
+	}
+}
+
+// VisitIPPort calls f.IPPort.
+func (f *VisitorFuncs) VisitIPPort(v netip.AddrPort) {
+	if f.IPPort != nil {
+		f.IPPort(v)
+	} else {
+		f.fallback(IPPort(v))
+	}
+}
+
+// VisitIPPrefix calls f.IPPrefix.
+func (f *VisitorFuncs) VisitIPPrefix(v netip.Prefix) {
+	if f.IPPrefix != nil {
+		f.IPPrefix(v)
+	} else {
+		f.fallback(IPPrefix(v))
+	}
+}
+
+// VisitMAC calls f.MAC.
+func (f *VisitorFuncs) VisitMAC(v net.HardwareAddr) {
+	if f.MAC != nil {
+		f.MAC(v)
+	} else {
+		f.fallback(MAC(v))
+	}
+}
+
+// VisitUUID calls f.UUID.
+func (f *VisitorFuncs) VisitUUID(v [16]byte) {
+	if f.UUID != nil {
+		f.UUID(v)
+	} else {
+		f.fallback(UUID(v))
+	}
+}
+
+// VisitRaw calls f.Raw.
+func (f *VisitorFuncs) VisitRaw(enc Encoding, data []byte) {
+	if f.Raw != nil {
+		f.Raw(enc, data)
+	} else {
+		f.fallback(Raw(enc, data))
+	}
+}
+
+// VisitEnum calls f.Enum.
+func (f *VisitorFuncs) VisitEnum(value int64, name string) {
+	if f.Enum != nil {
+		f.Enum(value, name)
+	} else {
+		f.fallback(Enum(value, name))
+	}
+}
+
+// VisitAnnotated calls f.Annotated. If it is not set, the inner value is
+// visited as if it had no annotations.
+func (f *VisitorFuncs) VisitAnnotated(v Value, annotations []Annotation) {
+	if f.Annotated != nil {
+		f.Annotated(v, annotations)
+	} else {
+		v.AcceptVisitor(f)
+	}
+}
+
+// VisitNull calls f.Null.
+func (f *VisitorFuncs) VisitNull() {
+	if f.Null != nil {
+		f.Null()
+	} else {
+		f.fallback(Null())
+	}
+}
+
+// VisitTimes calls f.Times.
+func (f *VisitorFuncs) VisitTimes(v []time.Time) {
+	if f.Times != nil {
+		f.Times(v)
+	} else {
+		f.fallback(Times(v))
+	}
+}
+
+// VisitErrors calls f.Errors.
+func (f *VisitorFuncs) VisitErrors(v []error) {
+	if f.Errors != nil {
+		f.Errors(v)
+	} else {
+		f.fallback(Errors(v))
+	}
+}
+
+// VisitStringers calls f.Stringers.
+func (f *VisitorFuncs) VisitStringers(v []fmt.Stringer) {
+	if f.Stringers != nil {
+		f.Stringers(v)
+	} else {
+		f.fallback(Stringers(v))
+	}
+}
+
+func (f *VisitorFuncs) fallback(v Value) {
+	if f.Fallback != nil {
+		f.Fallback(v)
+
+		return
+	}
+
+	err := fmt.Errorf("valf: unhandled value type: %v", v.Type())
+	if f.Err == nil {
+		f.Err = err
+	}
+	if f.Strict {
+		panic(err)
+	}
+}
@@ -0,0 +1,237 @@
+package valf
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisitorFuncs(t *testing.T) {
+	var ints []int
+	var strs []string
+	visitor := &VisitorFuncs{
+		Int:    func(v int) { ints = append(ints, v) },
+		String: func(v string) { strs = append(strs, v) },
+	}
+
+	Int(42).AcceptVisitor(visitor)
+	String("s").AcceptVisitor(visitor)
+	Bool(true).AcceptVisitor(visitor)
+	IP(netip.MustParseAddr("10.0.0.1")).AcceptVisitor(visitor)
+	Annotated(Int(43), Unit("ms")).AcceptVisitor(visitor)
+
+	require.Equal(t, []int{42, 43}, ints)
+	require.Equal(t, []string{"s"}, strs)
+	require.EqualError(t, visitor.Err, "valf: unhandled value type: bool")
+}
+
+func TestVisitorFuncsExtensions(t *testing.T) {
+	var visited []interface{}
+	visit := func(v interface{}) { visited = append(visited, v) }
+	visitor := &VisitorFuncs{
+		BigInt:       func(v *big.Int) { visit(v) },
+		BigFloat:     func(v *big.Float) { visit(v) },
+		BigRat:       func(v *big.Rat) { visit(v) },
+		Decimal:      func(c *big.Int, e int32) { visit(fmt.Sprint(c, "e", e)) },
+		Complex64:    func(v complex64) { visit(v) },
+		Complex128:   func(v complex128) { visit(v) },
+		Complexes64:  func(v []complex64) { visit(v) },
+		Complexes128: func(v []complex128) { visit(v) },
+		Uintptr:      func(v uintptr) { visit(v) },
+		Uintptrs:     func(v []uintptr) { visit(v) },
+		IP:           func(v netip.Addr) { visit(v) },
+		IPPort:       func(v netip.AddrPort) { visit(v) },
+		IPPrefix:     func(v netip.Prefix) { visit(v) },
+		MAC:          func(v net.HardwareAddr) { visit(v) },
+		UUID:         func(v [16]byte) { visit(v) },
+		Raw:          func(enc Encoding, data []byte) { visit(fmt.Sprint(enc, ":", string(data))) },
+		Enum:         func(v int64, name string) { visit(fmt.Sprint(v, ":", name)) },
+		Annotated:    func(v Value, a []Annotation) { visit(a) },
+		Null:         func() { visit(nil) },
+		Times:        func(v []time.Time) { visit(v) },
+		Errors:       func(v []error) { visit(v) },
+		Stringers:    func(v []fmt.Stringer) { visit(v) },
+		Strict:       true,
+	}
+
+	now := time.Now()
+	err := errors.New("e")
+	mac := net.HardwareAddr{1, 2, 3, 4, 5, 6}
+	values := []Value{
+		BigInt(big.NewInt(1)),
+		BigFloat(big.NewFloat(2)),
+		BigRat(big.NewRat(1, 3)),
+		Decimal(big.NewInt(4), -1),
+		Complex64(5),
+		Complex128(6),
+		Complexes64([]complex64{7}),
+		Complexes128([]complex128{8}),
+		Uintptr(9),
+		Uintptrs([]uintptr{10}),
+		IP(netip.MustParseAddr("10.0.0.1")),
+		IPPort(netip.MustParseAddrPort("10.0.0.1:80")),
+		IPPrefix(netip.MustParsePrefix("10.0.0.0/8")),
+		MAC(mac),
+		UUID([16]byte{11}),
+		RawJSON([]byte(`{"a":1}`)),
+		Enum(12, "twelve"),
+		Annotated(Int(13), Unit("ms")),
+		Null(),
+		Times([]time.Time{now}),
+		Errors([]error{err}),
+		Stringers([]fmt.Stringer{time.Second}),
+	}
+	expected := []interface{}{
+		big.NewInt(1),
+		big.NewFloat(2),
+		big.NewRat(1, 3),
+		"4e-1",
+		complex64(5),
+		complex128(6),
+		[]complex64{7},
+		[]complex128{8},
+		uintptr(9),
+		[]uintptr{10},
+		netip.MustParseAddr("10.0.0.1"),
+		netip.MustParseAddrPort("10.0.0.1:80"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		mac,
+		[16]byte{11},
+		`json:{"a":1}`,
+		"12:twelve",
+		[]Annotation{Unit("ms")},
+		nil,
+		[]time.Time{now},
+		[]error{err},
+		[]fmt.Stringer{time.Second},
+	}
+
+	require.NotPanics(t, func() {
+		for _, value := range values {
+			value.AcceptVisitor(visitor)
+		}
+	})
+	require.Equal(t, expected, visited)
+	require.NoError(t, visitor.Err)
+}
+
+func TestVisitorFuncsFallback(t *testing.T) {
+	now := time.Now()
+	values := []Value{
+		{},
+		Any(struct{}{}),
+		Bool(true),
+		Int(1),
+		Int8(2),
+		Int16(3),
+		Int32(4),
+		Int64(5),
+		Uint(6),
+		Uint8(7),
+		Uint16(8),
+		Uint32(9),
+		Uint64(10),
+		Float32(11),
+		Float64(12),
+		Duration(time.Second),
+		Error(nil),
+		Time(now),
+		String("s"),
+		Strings([]string{"s"}),
+		Bytes([]byte{1}),
+		Bools([]bool{true}),
+		Ints([]int{1}),
+		Ints8([]int8{2}),
+		Ints16([]int16{3}),
+		Ints32([]int32{4}),
+		Ints64([]int64{5}),
+		Uints([]uint{6}),
+		Uints8([]uint8{7}),
+		Uints16([]uint16{8}),
+		Uints32([]uint32{9}),
+		Uints64([]uint64{10}),
+		Floats32([]float32{11}),
+		Floats64([]float64{12}),
+		Durations([]time.Duration{time.Second}),
+		Array(mockArray{Int(1)}),
+		Object(mockObject{"a": Int(1)}),
+		BigInt(big.NewInt(13)),
+		BigFloat(big.NewFloat(14)),
+		BigRat(big.NewRat(1, 15)),
+		Decimal(big.NewInt(16), -1),
+		Complex64(17),
+		Complex128(18),
+		Complexes64([]complex64{19}),
+		Complexes128([]complex128{20}),
+		Uintptr(21),
+		Uintptrs([]uintptr{22}),
+		IP(netip.MustParseAddr("10.0.0.1")),
+		IP(netip.MustParseAddr("fe80::1%eth0")),
+		IPPort(netip.MustParseAddrPort("10.0.0.1:80")),
+		IPPrefix(netip.MustParsePrefix("10.0.0.0/8")),
+		MAC(net.HardwareAddr{1, 2, 3, 4, 5, 6}),
+		UUID([16]byte{23}),
+		Raw(EncodingJSON, []byte(`{"a":1}`)),
+		RawText([]byte("text")),
+		Enum(24, "name"),
+		Null(),
+		Times([]time.Time{now}),
+		Errors([]error{errors.New("e")}),
+		Stringers([]fmt.Stringer{time.Second}),
+	}
+
+	var visited []Value
+	visitor := &VisitorFuncs{Fallback: func(v Value) { visited = append(visited, v) }}
+	for _, value := range values {
+		value.AcceptVisitor(visitor)
+	}
+
+	require.Len(t, visited, len(values))
+	for i, value := range values {
+		require.Equal(t, value, visited[i], "value %d of type %v", i, value.Type())
+	}
+}
+
+func TestVisitorFuncsIgnore(t *testing.T) {
+	require.NotPanics(t, func() {
+		Int(1).AcceptVisitor(&VisitorFuncs{})
+		Object(nil).AcceptVisitor(&VisitorFuncs{})
+	})
+}
+
+func TestVisitorFuncsStrict(t *testing.T) {
+	visitor := &VisitorFuncs{
+		Int:    func(int) {},
+		Strict: true,
+	}
+	require.NotPanics(t, func() { Int(1).AcceptVisitor(visitor) })
+	require.Panics(t, func() { String("s").AcceptVisitor(visitor) })
+	require.Panics(t, func() { Value{}.AcceptVisitor(visitor) })
+
+	require.Panics(t, func() { IP(netip.MustParseAddr("10.0.0.1")).AcceptVisitor(visitor) })
+	require.Panics(t, func() { Null().AcceptVisitor(visitor) })
+	require.NotPanics(t, func() { Annotated(Int(1), Unit("ms")).AcceptVisitor(visitor) })
+
+	visitor.Fallback = func(Value) {}
+	require.NotPanics(t, func() { String("s").AcceptVisitor(visitor) })
+}
+
+func TestVisitorFuncsErr(t *testing.T) {
+	visitor := &VisitorFuncs{String: func(string) {}}
+	String("s").AcceptVisitor(visitor)
+	require.NoError(t, visitor.Err)
+
+	UUID([16]byte{1}).AcceptVisitor(visitor)
+	Enum(1, "one").AcceptVisitor(visitor)
+	require.EqualError(t, visitor.Err, "valf: unhandled value type: uuid")
+
+	visitor = &VisitorFuncs{Fallback: func(Value) {}}
+	Int(1).AcceptVisitor(visitor)
+	require.NoError(t, visitor.Err)
+}
@@ -0,0 +1,126 @@
+package valf
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Interner deduplicates strings so that equal strings of many snapshotted
+// values share the same memory. It is safe for concurrent use.
+//
+// The table of an Interner is bounded. When it is full, it is cleared and
+// starts over, so the most frequent strings get back into it quickly.
+// Strings longer than the maximum length are never interned since they are
+// unlikely to repeat.
+//
+// The zero value is an Interner with default limits ready to use.
+type Interner struct {
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	mu         sync.RWMutex
+	table      map[string]string
+	maxEntries int
+	maxLength  int
+}
+
+// Default limits of an Interner.
+const (
+	DefaultInternerMaxEntries = 4096
+	DefaultInternerMaxLength  = 64
+)
+
+// InternerStats contains metrics of an Interner.
+type InternerStats struct {
+	// Hits is the number of strings found in the table.
+	Hits uint64
+	// Misses is the number of strings not found in the table including too long ones.
+	Misses uint64
+	// Entries is the current number of strings in the table.
+	Entries int
+}
+
+// HitRate returns the ratio of hits to all lookups or zero if there were none.
+func (s InternerStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+// NewInterner returns a new Interner keeping up to maxEntries strings
+// no longer than maxLength bytes each. Zero limits are replaced with
+// DefaultInternerMaxEntries and DefaultInternerMaxLength.
+func NewInterner(maxEntries, maxLength int) *Interner {
+	return &Interner{maxEntries: maxEntries, maxLength: maxLength}
+}
+
+// Intern returns a string equal to s sharing memory with previously
+// interned equal strings.
+func (i *Interner) Intern(s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	if len(s) > i.maxLengthOrDefault() {
+		i.misses.Add(1)
+
+		return s
+	}
+
+	i.mu.RLock()
+	interned, ok := i.table[s]
+	i.mu.RUnlock()
+	if ok {
+		i.hits.Add(1)
+
+		return interned
+	}
+
+	i.misses.Add(1)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if interned, ok := i.table[s]; ok {
+		return interned
+	}
+	if i.table == nil || len(i.table) >= i.maxEntriesOrDefault() {
+		i.table = make(map[string]string)
+	}
+	// Clone s so that the table does not retain a larger string s may be part of.
+	s = strings.Clone(s)
+	i.table[s] = s
+
+	return s
+}
+
+// Stats returns the current metrics of the Interner.
+func (i *Interner) Stats() InternerStats {
+	i.mu.RLock()
+	entries := len(i.table)
+	i.mu.RUnlock()
+
+	return InternerStats{
+		Hits:    i.hits.Load(),
+		Misses:  i.misses.Load(),
+		Entries: entries,
+	}
+}
+
+func (i *Interner) maxEntriesOrDefault() int {
+	if i.maxEntries > 0 {
+		return i.maxEntries
+	}
+
+	return DefaultInternerMaxEntries
+}
+
+func (i *Interner) maxLengthOrDefault() int {
+	if i.maxLength > 0 {
+		return i.maxLength
+	}
+
+	return DefaultInternerMaxLength
+}
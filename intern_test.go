@@ -0,0 +1,102 @@
+package valf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sameString(a, b string) bool {
+	return unsafe.StringData(a) == unsafe.StringData(b)
+}
+
+func TestInterner(t *testing.T) {
+	i := NewInterner(2, 8)
+	a := i.Intern(strings.Repeat("a", 2))
+	require.Equal(t, "aa", a)
+	require.True(t, sameString(a, i.Intern(strings.Repeat("a", 2))))
+	require.Equal(t, InternerStats{Hits: 1, Misses: 1, Entries: 1}, i.Stats())
+	require.Equal(t, 0.5, i.Stats().HitRate())
+
+	long := strings.Repeat("x", 9)
+	require.True(t, sameString(long, i.Intern(long)))
+
+	i.Intern("b")
+	i.Intern("c")
+	require.Equal(t, 1, i.Stats().Entries)
+	require.Equal(t, 0.0, InternerStats{}.HitRate())
+}
+
+func TestInternerZero(t *testing.T) {
+	var i Interner
+	a := i.Intern(strings.Repeat("a", 2))
+	require.True(t, sameString(a, i.Intern(strings.Repeat("a", 2))))
+	require.Equal(t, InternerStats{Hits: 1, Misses: 1, Entries: 1}, i.Stats())
+
+	long := strings.Repeat("x", DefaultInternerMaxLength+1)
+	require.True(t, sameString(long, i.Intern(long)))
+	require.Equal(t, 1, i.Stats().Entries)
+
+	for n := 0; n != DefaultInternerMaxEntries; n++ {
+		i.Intern(fmt.Sprint(n))
+	}
+	require.Equal(t, 1, i.Stats().Entries)
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	i := NewInterner(10, 100)
+	var wg sync.WaitGroup
+	for g := 0; g != 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n != 100; n++ {
+				s := fmt.Sprint(n % 20)
+				require.Equal(t, s, i.Intern(s))
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := i.Stats()
+	require.Equal(t, uint64(400), stats.Hits+stats.Misses)
+	require.True(t, stats.Entries <= 10)
+}
+
+func TestSnapshotWithInterner(t *testing.T) {
+	i := NewInterner(100, 100)
+	key := func() string {
+		return strings.Repeat("k", 3)
+	}
+
+	var values []Value
+	for n := 0; n != 2; n++ {
+		s := testMutableStringer(strings.Repeat("s", 3))
+		v := Array(mockArray{
+			Object(mockObject{key(): Stringer(&s)}),
+			String(strings.Repeat("v", 3)),
+			Strings([]string{strings.Repeat("v", 3)}),
+		})
+		SnapshotWith(&v, SnapshotOptions{Interner: i})
+		values = append(values, v)
+	}
+
+	items := [2][]Value{}
+	for n, v := range values {
+		visitor := newMockArrayVisitor(t)
+		v.AcceptVisitor(visitor)
+		items[n] = visitor.value
+	}
+
+	first := items[0][0].vAny.(objectSnapshot).fields[0]
+	second := items[1][0].vAny.(objectSnapshot).fields[0]
+	require.True(t, sameString(first.Name, second.Name))
+	require.True(t, sameString(first.Value.vString, second.Value.vString))
+	require.True(t, sameString(items[0][1].vString, items[1][1].vString))
+	require.True(t, sameString(items[0][1].vString, items[1][2].vAny.([]string)[0]))
+	require.True(t, i.Stats().Hits >= 4)
+}
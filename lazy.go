@@ -0,0 +1,47 @@
+package valf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Valuer is the interface that allows a type to provide its own Value
+// representation which is evaluated only when it is needed.
+// Any and ConstAny return lazy values for types implementing Valuer.
+type Valuer interface {
+	Value() Value
+}
+
+// MaxLazyDepth is the maximum number of nested lazy values resolved in a row.
+// It protects against valuers infinitely returning other lazy values.
+const MaxLazyDepth = 100
+
+type lazyFunc func() Value
+
+func (f lazyFunc) Value() Value {
+	return f()
+}
+
+// resolveLazy calls v and all lazy values it returns until the result is
+// not lazy. Panics are recovered and reported as error values.
+func resolveLazy(v Valuer) Value {
+	for i := 0; i != MaxLazyDepth; i++ {
+		result := callValuer(v)
+		if result.bits.Type() != TypeLazy {
+			return result
+		}
+		v = result.vAny.(Valuer)
+	}
+
+	return ConstError(errors.New("valf: too many nested lazy values"))
+}
+
+func callValuer(v Valuer) (result Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = ConstError(fmt.Errorf("valf: lazy value panicked: %v", r))
+		}
+	}()
+
+	return v.Value()
+}
@@ -0,0 +1,106 @@
+package valf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testValuer struct {
+	calls *int
+}
+
+func (v testValuer) Value() Value {
+	*v.calls++
+
+	return String("resolved")
+}
+
+type testRecursiveValuer struct{}
+
+func (v testRecursiveValuer) Value() Value {
+	return Any(v)
+}
+
+func TestValueLazy(t *testing.T) {
+	calls := 0
+	value := Lazy(func() Value {
+		calls++
+
+		return Int(42)
+	})
+	require.Equal(t, TypeLazy, value.Type())
+	require.Equal(t, false, value.Const())
+	require.Equal(t, 0, calls)
+
+	visitor := newMockIntVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, 42, visitor.value)
+	require.Equal(t, 1, calls)
+}
+
+func TestValueLazySnapshot(t *testing.T) {
+	calls := 0
+	value := Any(testValuer{&calls}).Snapshot()
+	require.Equal(t, 1, calls)
+	require.Equal(t, String("resolved"), value)
+
+	value.AcceptVisitor(newMockStringVisitor(t))
+	require.Equal(t, 1, calls)
+}
+
+func TestValueConstAnyValuer(t *testing.T) {
+	calls := 0
+	value := ConstAny(testValuer{&calls})
+	require.Equal(t, TypeLazy, value.Type())
+	require.Equal(t, String("resolved"), value.Resolve())
+	require.Equal(t, 1, calls)
+}
+
+func TestValueLazyNested(t *testing.T) {
+	value := Lazy(func() Value {
+		return Lazy(func() Value {
+			return String("nested")
+		})
+	})
+	require.Equal(t, String("nested"), value.Resolve())
+	require.Equal(t, String("test"), String("test").Resolve())
+}
+
+func TestValueLazyRecursion(t *testing.T) {
+	visitor := newMockErrorVisitor(t)
+	Any(testRecursiveValuer{}).AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, "valf: too many nested lazy values", visitor.value.Error())
+}
+
+func TestValueLazyPanic(t *testing.T) {
+	visitor := newMockErrorVisitor(t)
+	Lazy(func() Value { panic("failure") }).AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, "valf: lazy value panicked: failure", visitor.value.Error())
+}
+
+func TestValueLazyNil(t *testing.T) {
+	require.Equal(t, Any(nil), Lazy(nil))
+	require.Equal(t, Any(nil), LazyValuer(nil))
+}
+
+func TestRedactLazy(t *testing.T) {
+	r := NewRedactor(RedactKeys(MaskWith("***"), "password"))
+	value := r.Redact(Object(mockObject{
+		"user": Lazy(func() Value {
+			return Object(mockObject{"password": String("secret")})
+		}),
+	}))
+
+	fields := redactedFields(t, redactedFields(t, value)["user"])
+	require.Equal(t, String("***"), fields["password"])
+}
+
+func TestLimitLazy(t *testing.T) {
+	value := Limit(Lazy(func() Value { return String("abcdef") }), Limits{MaxBytes: 3})
+	require.Equal(t, String("abc…"), value.Resolve())
+	require.Equal(t, String("abc…"), value.Resolve())
+}
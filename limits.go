@@ -0,0 +1,327 @@
+package valf
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits defines bounds applied to a value tree while it is traversed or
+// snapshotted. A zero value of any field means that the corresponding
+// dimension is not limited.
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of arrays and objects.
+	// Arrays and objects nested deeper are replaced with a truncation marker.
+	MaxDepth int
+
+	// MaxArrayItems is the maximum number of items per array including typed slices.
+	// Arrays with more items get a truncation marker as the last item.
+	MaxArrayItems int
+
+	// MaxObjectFields is the maximum number of fields per object.
+	// Objects with more fields get a truncation marker as the last field.
+	MaxObjectFields int
+
+	// MaxBytes is the maximum total number of bytes in strings and byte slices of the whole tree.
+	// Strings which do not fit are cut and get a truncation marker at the end.
+	// Text of Stringers, Formatters and errors is counted as well, so such
+	// values are rendered to strings, and errors are replaced with errors
+	// having the cut message but neither causes nor fields. Raw data is
+	// counted as well. Raw JSON is decoded and limited as any other value
+	// if it does not fit or other limits are set.
+	MaxBytes int
+}
+
+// TruncationMarker is used to denote the data omitted due to the limits.
+const TruncationMarker = "…"
+
+// Limit returns a Value which applies the limits to v lazily, i.e. while it is being visited.
+// Each traversal of the returned value starts with a full byte budget.
+func Limit(v Value, limits Limits) Value {
+	l := limiter{limits, limits.MaxBytes}
+
+	return l.limit(v, 0, true)
+}
+
+// SnapshotLimited changes the v in the same way as Snapshot does but
+// applies the limits so that it never does unbounded work even for
+// self-referential or enormous arrays and objects.
+func SnapshotLimited(v *Value, limits Limits) {
+	*v = Limit(*v, limits)
+	Snapshot(v)
+}
+
+// SnapshotLimited returns a Value which can be safely stored for a long
+// with guarantee that it won't be modified, applying the limits.
+func (v Value) SnapshotLimited(limits Limits) Value {
+	SnapshotLimited(&v, limits)
+
+	return v
+}
+
+type limiter struct {
+	limits Limits
+	bytes  int
+}
+
+func (l *limiter) limit(v Value, depth int, root bool) Value {
+	switch v.bits.Type() {
+	case TypeString:
+		v.vString = l.limitString(v.vString)
+	case TypeBytes, TypeMAC:
+		v.vBytes = l.limitBytes(v.vBytes)
+	case TypeRaw:
+		return l.limitRaw(v, depth, root)
+	case TypeStrings:
+		v.vAny = l.limitStrings(v.vAny.([]string))
+	case TypeStringer, TypeFormatter:
+		if v.vAny != nil && l.limits.MaxBytes != 0 {
+			return String(l.limitString(valueText(v)))
+		}
+	case TypeError:
+		if v.vAny != nil && l.limits.MaxBytes != 0 {
+			err := v.vAny.(error)
+
+			return ConstError(textError(err, l.limitString(err.Error())))
+		}
+	case TypeTimes:
+		v.vAny = limitSlice(l, v.vAny.([]time.Time))
+	case TypeErrors:
+		s := limitSlice(l, v.vAny.([]error))
+		if l.limits.MaxBytes != 0 {
+			return ConstStrings(l.limitStrings(errorsText(s)))
+		}
+		v.vAny = s
+	case TypeStringers:
+		s := limitSlice(l, v.vAny.([]fmt.Stringer))
+		if l.limits.MaxBytes != 0 {
+			return ConstStrings(l.limitStrings(stringersText(s)))
+		}
+		v.vAny = s
+	case TypeBools, TypeInts, TypeInts8, TypeInts16, TypeInts32, TypeInts64,
+		TypeUints, TypeUints8, TypeUints16, TypeUints32, TypeUints64,
+		TypeFloats32, TypeFloats64, TypeDurations,
+		TypeComplexes64, TypeComplexes128, TypeUintptrs:
+		// Typed slices are stored in vBytes with length measured in items,
+		// so they can be resliced without knowing the item type.
+		v.vBytes = l.limitItems(v.vBytes)
+	case TypeArray:
+		if v.vAny != nil {
+			if l.exceedsDepth(depth) {
+				return String(TruncationMarker)
+			}
+			v.vAny = limitedArray{v.vAny.(ValueArray), l, depth + 1, root}
+		}
+	case TypeObject:
+		if v.vAny != nil {
+			if l.exceedsDepth(depth) {
+				return String(TruncationMarker)
+			}
+			v.vAny = limitedObject{v.vAny.(ValueObject), l, depth + 1, root}
+		}
+	case TypeLazy:
+		return Lazy(func() Value {
+			return l.fresh(root).limit(v.Resolve(), depth, root)
+		})
+	case TypeAnnotated:
+		return reannotate(v, l.limit(v.Unannotated(), depth, root))
+	}
+
+	return v
+}
+
+// limitRaw counts raw data against the byte budget. JSON data is decoded
+// and limited as any other value unless it fits in the budget and there
+// are no limits for arrays and objects. Other data which does not fit is
+// cut, text becomes a string and data in other encodings becomes bytes,
+// since cut data is not well-formed anymore.
+func (l *limiter) limitRaw(v Value, depth int, root bool) Value {
+	enc := Encoding(v.vInt)
+	fits := l.limits.MaxBytes == 0 || len(v.vBytes) <= l.bytes
+	if enc == EncodingJSON && (!fits || l.limits.MaxDepth != 0 || l.limits.MaxArrayItems != 0 || l.limits.MaxObjectFields != 0) {
+		if decoded, err := v.decodeRawJSON(); err == nil {
+			return l.limit(decoded, depth, root)
+		}
+
+		return String(l.limitString(string(v.vBytes)))
+	}
+
+	switch {
+	case fits:
+		l.limitBytes(v.vBytes)
+
+		return v
+	case enc == EncodingText:
+		return String(l.limitString(string(v.vBytes)))
+	}
+
+	return Bytes(l.limitBytes(v.vBytes))
+}
+
+func (l *limiter) exceedsDepth(depth int) bool {
+	return l.limits.MaxDepth != 0 && depth >= l.limits.MaxDepth
+}
+
+func (l *limiter) limitItems(s []byte) []byte {
+	if l.limits.MaxArrayItems != 0 && len(s) > l.limits.MaxArrayItems {
+		return s[:l.limits.MaxArrayItems:l.limits.MaxArrayItems]
+	}
+
+	return s
+}
+
+func (l *limiter) limitBytes(s []byte) []byte {
+	if l.limits.MaxBytes == 0 {
+		return s
+	}
+	if len(s) > l.bytes {
+		s = s[:l.bytes:l.bytes]
+	}
+	l.bytes -= len(s)
+
+	return s
+}
+
+func (l *limiter) limitString(s string) string {
+	if l.limits.MaxBytes == 0 {
+		return s
+	}
+	if len(s) <= l.bytes {
+		l.bytes -= len(s)
+
+		return s
+	}
+
+	n := l.bytes
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	l.bytes = 0
+
+	return s[:n] + TruncationMarker
+}
+
+func limitSlice[T any](l *limiter, s []T) []T {
+	if l.limits.MaxArrayItems != 0 && len(s) > l.limits.MaxArrayItems {
+		return s[:l.limits.MaxArrayItems:l.limits.MaxArrayItems]
+	}
+
+	return s
+}
+
+func (l *limiter) limitStrings(s []string) []string {
+	if l.limits.MaxArrayItems != 0 && len(s) > l.limits.MaxArrayItems {
+		s = s[:l.limits.MaxArrayItems:l.limits.MaxArrayItems]
+	}
+	if l.limits.MaxBytes == 0 {
+		return s
+	}
+
+	var result []string
+	for i, item := range s {
+		limited := l.limitString(item)
+		if result == nil && len(limited) != len(item) {
+			result = make([]string, len(s))
+			copy(result, s[:i])
+		}
+		if result != nil {
+			result[i] = limited
+		}
+	}
+	if result == nil {
+		return s
+	}
+
+	return result
+}
+
+// fresh returns limiter for a new traversal if root is true.
+func (l *limiter) fresh(root bool) *limiter {
+	if !root {
+		return l
+	}
+
+	return &limiter{l.limits, l.limits.MaxBytes}
+}
+
+type limitedArray struct {
+	array ValueArray
+	l     *limiter
+	depth int
+	root  bool
+}
+
+func (a limitedArray) ArrayItemCount() int {
+	n := a.array.ArrayItemCount()
+	if maxItems := a.l.limits.MaxArrayItems; maxItems != 0 && n > maxItems {
+		return maxItems + 1
+	}
+
+	return n
+}
+
+func (a limitedArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	lv := limitedArrayItemVisitor{visitor, a.l.fresh(a.root), a.depth, 0}
+	a.array.AcceptArrayItemVisitor(&lv)
+
+	if maxItems := a.l.limits.MaxArrayItems; maxItems != 0 && lv.count > maxItems {
+		visitor.VisitArrayItem(maxItems, String(fmt.Sprintf("%s+%d more items", TruncationMarker, lv.count-maxItems)))
+	}
+}
+
+type limitedArrayItemVisitor struct {
+	visitor ArrayItemVisitor
+	l       *limiter
+	depth   int
+	count   int
+}
+
+func (v *limitedArrayItemVisitor) VisitArrayItem(index int, value Value) {
+	v.count++
+	if maxItems := v.l.limits.MaxArrayItems; maxItems != 0 && index >= maxItems {
+		return
+	}
+
+	v.visitor.VisitArrayItem(index, v.l.limit(value, v.depth, false))
+}
+
+type limitedObject struct {
+	object ValueObject
+	l      *limiter
+	depth  int
+	root   bool
+}
+
+func (o limitedObject) ObjectFieldCount() int {
+	n := o.object.ObjectFieldCount()
+	if maxFields := o.l.limits.MaxObjectFields; maxFields != 0 && n > maxFields {
+		return maxFields + 1
+	}
+
+	return n
+}
+
+func (o limitedObject) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	lv := limitedObjectFieldVisitor{visitor, o.l.fresh(o.root), o.depth, 0}
+	o.object.AcceptObjectFieldVisitor(&lv)
+
+	if maxFields := o.l.limits.MaxObjectFields; maxFields != 0 && lv.count > maxFields {
+		visitor.VisitObjectField(TruncationMarker, String(fmt.Sprintf("%s+%d more fields", TruncationMarker, lv.count-maxFields)))
+	}
+}
+
+type limitedObjectFieldVisitor struct {
+	visitor ObjectFieldVisitor
+	l       *limiter
+	depth   int
+	count   int
+}
+
+func (v *limitedObjectFieldVisitor) VisitObjectField(key string, value Value) {
+	v.count++
+	if maxFields := v.l.limits.MaxObjectFields; maxFields != 0 && v.count > maxFields {
+		return
+	}
+
+	v.visitor.VisitObjectField(key, v.l.limit(value, v.depth, false))
+}
@@ -0,0 +1,157 @@
+package valf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type selfReferencingObject struct{}
+
+func (o selfReferencingObject) ObjectFieldCount() int {
+	return 1
+}
+
+func (o selfReferencingObject) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	visitor.VisitObjectField("self", Object(o))
+}
+
+type hugeArray int
+
+func (a hugeArray) ArrayItemCount() int {
+	return int(a)
+}
+
+func (a hugeArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i := 0; i != int(a); i++ {
+		visitor.VisitArrayItem(i, Int(i))
+	}
+}
+
+func TestLimitDepth(t *testing.T) {
+	value := Object(selfReferencingObject{}).SnapshotLimited(Limits{MaxDepth: 3})
+	require.Equal(t, true, value.Const())
+
+	for i := 0; i != 3; i++ {
+		visitor := newMockObjectVisitor(t)
+		value.AcceptVisitor(visitor)
+		require.Equal(t, true, visitor.visited)
+		require.Equal(t, 1, visitor.count)
+		value = visitor.value["self"]
+	}
+	require.Equal(t, String(TruncationMarker), value)
+}
+
+func TestLimitArrayItems(t *testing.T) {
+	value := Array(hugeArray(1000)).SnapshotLimited(Limits{MaxArrayItems: 2})
+	visitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, []Value{Int(0), Int(1), String("…+998 more items")}, visitor.value)
+}
+
+func TestLimitObjectFields(t *testing.T) {
+	value := Limit(Object(mockObject{"a": Int(1), "b": Int(2), "c": Int(3)}), Limits{MaxObjectFields: 1})
+	visitor := newMockObjectVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, 2, visitor.count)
+	require.Equal(t, 2, len(visitor.value))
+	require.Equal(t, String("…+2 more fields"), visitor.value[TruncationMarker])
+}
+
+func TestLimitBytes(t *testing.T) {
+	limits := Limits{MaxBytes: 10}
+	value := Limit(Array(mockArray{String("abcdef"), Bytes([]byte("ghi")), String("jklmno"), String("pqr")}), limits)
+
+	// Each traversal gets the full budget.
+	for i := 0; i != 2; i++ {
+		visitor := newMockArrayVisitor(t)
+		value.AcceptVisitor(visitor)
+		require.Equal(t, true, visitor.visited)
+		require.Equal(t, []Value{String("abcdef"), Bytes([]byte("ghi")), String("j…"), String("…")}, visitor.value)
+	}
+
+	require.Equal(t, String("абв…"), Limit(String("абвгд"), Limits{MaxBytes: 7}))
+	require.Equal(t, ConstStrings([]string{"abc", "de…"}), Limit(ConstStrings([]string{"abc", "defg", "h"}), Limits{MaxBytes: 5, MaxArrayItems: 2}))
+}
+
+type testLongStringer int
+
+func (s testLongStringer) String() string {
+	return strings.Repeat("a", int(s))
+}
+
+func TestLimitText(t *testing.T) {
+	limits := Limits{MaxBytes: 10}
+	require.Equal(t, String("aaaaaaaaaa…"), Stringer(testLongStringer(1000)).SnapshotLimited(limits))
+	require.Equal(t, String("aaaaaaaaaa…"), Formatter("%v", testLongStringer(1000)).SnapshotLimited(limits))
+	require.Equal(t, Stringer(nil), Stringer(nil).SnapshotLimited(limits))
+
+	value := Error(fmt.Errorf("wrapped: %w", errors.New(strings.Repeat("b", 1000)))).SnapshotLimited(limits)
+	visitor := newMockErrorVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, "wrapped: b…", visitor.value.Error())
+	require.Nil(t, errors.Unwrap(visitor.value))
+	require.Equal(t, String("*fmt.wrapError"), errorDetailsFields(t, ErrorDetails(visitor.value))[ErrorFieldType])
+
+	require.Equal(t,
+		ConstStrings([]string{"aaaaaa", "<nil…", "…"}),
+		Stringers([]fmt.Stringer{testLongStringer(6), nil, testLongStringer(6)}).SnapshotLimited(limits),
+	)
+	require.Equal(t,
+		ConstStrings([]string{"aaaaaaaaaa…"}),
+		Errors([]error{errors.New(strings.Repeat("a", 100)), nil}).SnapshotLimited(Limits{MaxBytes: 10, MaxArrayItems: 1}),
+	)
+}
+
+func TestLimitRaw(t *testing.T) {
+	items := make([]string, 250)
+	for i := range items {
+		items[i] = "1"
+	}
+	data := []byte("[" + strings.Join(items, ",") + "]")
+
+	value := Limit(RawJSON(data), Limits{MaxBytes: 10, MaxArrayItems: 2})
+	visitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, []Value{Int64(1), Int64(1), String("…+248 more items")}, visitor.value)
+
+	value = Limit(RawJSON([]byte(`{"a": "abcdefghijkl"}`)), Limits{MaxBytes: 5})
+	objectVisitor := newMockObjectVisitor(t)
+	value.AcceptVisitor(objectVisitor)
+	require.Equal(t, map[string]Value{"a": String("abcde…")}, objectVisitor.value)
+
+	value = Limit(RawJSON([]byte(`[[[1]]]`)), Limits{MaxDepth: 1})
+	visitor = newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []Value{String(TruncationMarker)}, visitor.value)
+
+	// Data which fits is kept as is.
+	require.Equal(t, RawJSON([]byte(`[1]`)), Limit(RawJSON([]byte(`[1]`)), Limits{MaxBytes: 5}))
+	require.Equal(t, RawText([]byte("abc")), Limit(RawText([]byte("abc")), Limits{MaxBytes: 5}))
+
+	require.Equal(t, String("abcde…"), Limit(RawText([]byte("abcdefgh")), Limits{MaxBytes: 5}))
+	require.Equal(t, Bytes([]byte{1, 2}), Limit(Raw(EncodingCBOR, []byte{1, 2, 3}), Limits{MaxBytes: 2}))
+	require.Equal(t, String("{\"a\"…"), Limit(RawJSON([]byte(`{"a": `)), Limits{MaxBytes: 4}))
+	require.Equal(t, MAC([]byte{1, 2}), Limit(MAC([]byte{1, 2, 3, 4, 5, 6}), Limits{MaxBytes: 2}))
+}
+
+func TestLimitTypedSlices(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	value := Ints(s).SnapshotLimited(Limits{MaxArrayItems: 3})
+	s[0] = 42
+	require.Equal(t, ConstInts([]int{1, 2, 3}), value)
+}
+
+func TestLimitNone(t *testing.T) {
+	v := mockArray{Int(42), String("test")}
+	value := Array(v).SnapshotLimited(Limits{})
+	visitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []Value(v), visitor.value)
+}
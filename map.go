@@ -0,0 +1,140 @@
+package valf
+
+import (
+	"sort"
+)
+
+// MapOption modifies the way map values are presented.
+type MapOption uint8
+
+// Valid values for MapOption.
+const (
+	// SortKeys makes map values visit their fields in ascending order of keys
+	// instead of the random map iteration order.
+	SortKeys MapOption = 1 << iota
+)
+
+// StringMap returns a new Value with the given map of strings presented as ValueObject.
+func StringMap(v map[string]string, options ...MapOption) Value {
+	return MapOf(v, options...)
+}
+
+// ConstStringMap returns a new Value with the given map of strings presented as ValueObject.
+//
+// Call ConstStringMap if your map is const. It has significantly less impact
+// on the calling goroutine.
+func ConstStringMap(v map[string]string, options ...MapOption) Value {
+	return ConstMapOf(v, options...)
+}
+
+// IntMap returns a new Value with the given map of ints presented as ValueObject.
+func IntMap(v map[string]int, options ...MapOption) Value {
+	return MapOf(v, options...)
+}
+
+// ConstIntMap returns a new Value with the given map of ints presented as ValueObject.
+//
+// Call ConstIntMap if your map is const. It has significantly less impact
+// on the calling goroutine.
+func ConstIntMap(v map[string]int, options ...MapOption) Value {
+	return ConstMapOf(v, options...)
+}
+
+// Map returns a new Value with the given map of values of any type presented
+// as ValueObject. Map values are converted using Any.
+func Map(v map[string]interface{}, options ...MapOption) Value {
+	return MapOf(v, options...)
+}
+
+// ConstMap returns a new Value with the given map of values of any type
+// presented as ValueObject. Map values are converted using ConstAny.
+//
+// Call ConstMap if your map and its values are const. It has significantly
+// less impact on the calling goroutine.
+func ConstMap(v map[string]interface{}, options ...MapOption) Value {
+	return ConstMapOf(v, options...)
+}
+
+// ValueMap returns a new Value with the given map of Values presented as ValueObject.
+func ValueMap(v map[string]Value, options ...MapOption) Value {
+	return MapOf(v, options...)
+}
+
+// ConstValueMap returns a new Value with the given map of Values presented as ValueObject.
+//
+// Call ConstValueMap if your map and its values are const. It has
+// significantly less impact on the calling goroutine.
+func ConstValueMap(v map[string]Value, options ...MapOption) Value {
+	return ConstMapOf(v, options...)
+}
+
+// MapOf returns a new Value with the given map presented as ValueObject.
+// Map values are converted using Any.
+func MapOf[K ~string, V any](v map[K]V, options ...MapOption) Value {
+	if v == nil {
+		return ConstObject(nil)
+	}
+
+	return Object(mapObject[K, V]{v, mapOptions(options), false})
+}
+
+// ConstMapOf returns a new Value with the given map presented as ValueObject.
+// Map values are converted using ConstAny.
+//
+// Call ConstMapOf if your map and its values are const. It has
+// significantly less impact on the calling goroutine.
+func ConstMapOf[K ~string, V any](v map[K]V, options ...MapOption) Value {
+	if v == nil {
+		return ConstObject(nil)
+	}
+
+	return ConstObject(mapObject[K, V]{v, mapOptions(options), true})
+}
+
+func mapOptions(options []MapOption) MapOption {
+	var result MapOption
+	for _, option := range options {
+		result |= option
+	}
+
+	return result
+}
+
+type mapObject[K ~string, V any] struct {
+	m       map[K]V
+	options MapOption
+	isConst bool
+}
+
+func (o mapObject[K, V]) ObjectFieldCount() int {
+	return len(o.m)
+}
+
+func (o mapObject[K, V]) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	if o.options&SortKeys == 0 {
+		for key, value := range o.m {
+			visitor.VisitObjectField(string(key), o.value(value))
+		}
+
+		return
+	}
+
+	keys := make([]K, 0, len(o.m))
+	for key := range o.m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		visitor.VisitObjectField(string(key), o.value(o.m[key]))
+	}
+}
+
+func (o mapObject[K, V]) value(v V) Value {
+	if o.isConst {
+		return ConstAny(v)
+	}
+
+	return Any(v)
+}
@@ -0,0 +1,126 @@
+package valf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type orderedObjectVisitor struct {
+	mockObjectVisitor
+	keys []string
+}
+
+func newOrderedObjectVisitor(t *testing.T) *orderedObjectVisitor {
+	return &orderedObjectVisitor{mockObjectVisitor: mockObjectVisitor{mockVisitor: mockVisitor{t}}}
+}
+
+func (v *orderedObjectVisitor) VisitObject(object ValueObject) {
+	if object != nil {
+		v.value = map[string]Value{}
+		v.count = object.ObjectFieldCount()
+		object.AcceptObjectFieldVisitor(v)
+	}
+	v.visited = true
+}
+
+func (v *orderedObjectVisitor) VisitObjectField(key string, value Value) {
+	v.keys = append(v.keys, key)
+	v.value[key] = value
+}
+
+func TestValueStringMap(t *testing.T) {
+	visitor := newOrderedObjectVisitor(t)
+	value := StringMap(map[string]string{"b": "2", "a": "1", "c": "3"}, SortKeys)
+	require.Equal(t, TypeObject, value.Type())
+	require.Equal(t, false, value.Const())
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, 3, visitor.count)
+	require.Equal(t, []string{"a", "b", "c"}, visitor.keys)
+	require.Equal(t, String("2"), visitor.value["b"])
+}
+
+func TestValueConstIntMap(t *testing.T) {
+	visitor := newMockObjectVisitor(t)
+	value := ConstIntMap(map[string]int{"a": 1, "b": 2})
+	require.Equal(t, true, value.Const())
+	value.AcceptVisitor(visitor)
+	require.Equal(t, map[string]Value{"a": Int(1), "b": Int(2)}, visitor.value)
+}
+
+func TestValueMap(t *testing.T) {
+	visitor := newMockObjectVisitor(t)
+	value := Map(map[string]interface{}{"a": 1, "b": []int{1, 2}, "c": nil})
+	require.Equal(t, false, value.Const())
+	value.AcceptVisitor(visitor)
+	require.Equal(t, map[string]Value{"a": Int(1), "b": Ints([]int{1, 2}), "c": Any(nil)}, visitor.value)
+}
+
+func TestValueConstMap(t *testing.T) {
+	visitor := newMockObjectVisitor(t)
+	value := ConstMap(map[string]interface{}{"b": []int{1, 2}})
+	require.Equal(t, true, value.Const())
+	value.AcceptVisitor(visitor)
+	require.Equal(t, map[string]Value{"b": ConstInts([]int{1, 2})}, visitor.value)
+}
+
+func TestValueValueMap(t *testing.T) {
+	visitor := newMockObjectVisitor(t)
+	v := map[string]Value{"a": Int(1), "b": Bytes([]byte("x"))}
+	value := ValueMap(v)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	visitor = newMockObjectVisitor(t)
+	ConstValueMap(v).AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+}
+
+type customKey string
+
+func TestValueMapOf(t *testing.T) {
+	visitor := newOrderedObjectVisitor(t)
+	value := MapOf(map[customKey]float64{"y": 2, "x": 1}, SortKeys)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []string{"x", "y"}, visitor.keys)
+	require.Equal(t, Float64(2), visitor.value["y"])
+}
+
+func TestValueNilMap(t *testing.T) {
+	visitor := newMockObjectVisitor(t)
+	value := Map(nil)
+	require.Equal(t, true, value.Const())
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, map[string]Value(nil), visitor.value)
+	require.Equal(t, ConstObject(nil), ConstStringMap(nil))
+}
+
+func TestValueAnyMap(t *testing.T) {
+	require.Equal(t, StringMap(map[string]string{"a": "1"}), Any(map[string]string{"a": "1"}))
+	require.Equal(t, ConstStringMap(map[string]string{"a": "1"}), ConstAny(map[string]string{"a": "1"}))
+	require.Equal(t, IntMap(map[string]int{"a": 1}), Any(map[string]int{"a": 1}))
+	require.Equal(t, ConstIntMap(map[string]int{"a": 1}), ConstAny(map[string]int{"a": 1}))
+	require.Equal(t, Map(map[string]interface{}{"a": 1}), Any(map[string]interface{}{"a": 1}))
+	require.Equal(t, ConstMap(map[string]interface{}{"a": 1}), ConstAny(map[string]interface{}{"a": 1}))
+	require.Equal(t, ValueMap(map[string]Value{"a": Int(1)}), Any(map[string]Value{"a": Int(1)}))
+	require.Equal(t, ConstValueMap(map[string]Value{"a": Int(1)}), ConstAny(map[string]Value{"a": Int(1)}))
+	require.Equal(t, Int(1), Any(Int(1)))
+	require.Equal(t, Int(1), ConstAny(Int(1)))
+}
+
+func TestMapSnapshot(t *testing.T) {
+	v := map[string]interface{}{"a": 1, "b": []int{1, 2}}
+	value := Map(v, SortKeys).Snapshot()
+	require.Equal(t, true, value.Const())
+	v["a"] = 2
+	v["b"].([]int)[0] = 42
+	v["c"] = 3
+
+	visitor := newOrderedObjectVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []string{"a", "b"}, visitor.keys)
+	require.Equal(t, Int(1), visitor.value["a"])
+	require.Equal(t, ConstInts([]int{1, 2}), visitor.value["b"])
+}
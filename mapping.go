@@ -0,0 +1,119 @@
+package valf
+
+// treeMapper defines a lazy transformation of a value tree.
+// Path contains keys of the objects enclosing the value being mapped,
+// arrays do not add path segments.
+type treeMapper interface {
+	// mapField maps an object field and reports whether the field should be kept.
+	mapField(path []string, key string, value Value) (string, Value, bool)
+	// mapItem maps an array item or a root value.
+	mapItem(path []string, value Value) Value
+	// filtersFields reports whether mapField may drop fields.
+	filtersFields() bool
+}
+
+// mapTree returns a Value which applies m to all nested values of v lazily.
+// The root value itself is not passed to m.
+func mapTree(m treeMapper, path []string, v Value) Value {
+	switch v.bits.Type() {
+	case TypeArray:
+		if v.vAny != nil {
+			return Array(mappedArray{v.vAny.(ValueArray), m, path})
+		}
+	case TypeObject:
+		if v.vAny != nil {
+			return Object(mappedObject{v.vAny.(ValueObject), m, path})
+		}
+	case TypeLazy:
+		return Lazy(func() Value {
+			return mapTree(m, path, m.mapItem(path, v.Resolve()))
+		})
+	case TypeAnnotated:
+		inner := v.Unannotated()
+
+		return reannotate(v, mapTree(m, path, m.mapItem(path, inner)))
+	}
+
+	return v
+}
+
+// mapRoot applies m to v and then to all its nested values.
+func mapRoot(m treeMapper, v Value) Value {
+	return mapTree(m, nil, m.mapItem(nil, v))
+}
+
+type mappedArray struct {
+	array  ValueArray
+	mapper treeMapper
+	path   []string
+}
+
+func (a mappedArray) ArrayItemCount() int {
+	return a.array.ArrayItemCount()
+}
+
+func (a mappedArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	a.array.AcceptArrayItemVisitor(&mappedArrayItemVisitor{visitor, a})
+}
+
+type mappedArrayItemVisitor struct {
+	visitor ArrayItemVisitor
+	array   mappedArray
+}
+
+func (v *mappedArrayItemVisitor) VisitArrayItem(index int, value Value) {
+	value = v.array.mapper.mapItem(v.array.path, value)
+	v.visitor.VisitArrayItem(index, mapTree(v.array.mapper, v.array.path, value))
+}
+
+type mappedObject struct {
+	object ValueObject
+	mapper treeMapper
+	path   []string
+}
+
+func (o mappedObject) ObjectFieldCount() int {
+	if !o.mapper.filtersFields() {
+		return o.object.ObjectFieldCount()
+	}
+
+	counter := mappedObjectFieldCounter{o, 0}
+	o.object.AcceptObjectFieldVisitor(&counter)
+
+	return counter.count
+}
+
+func (o mappedObject) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	o.object.AcceptObjectFieldVisitor(&mappedObjectFieldVisitor{visitor, o})
+}
+
+type mappedObjectFieldVisitor struct {
+	visitor ObjectFieldVisitor
+	object  mappedObject
+}
+
+func (v *mappedObjectFieldVisitor) VisitObjectField(key string, value Value) {
+	key, value, keep := v.object.mapper.mapField(v.object.path, key, value)
+	if !keep {
+		return
+	}
+
+	switch value.bits.Type() {
+	case TypeArray, TypeObject, TypeLazy, TypeAnnotated:
+		path := append(v.object.path[:len(v.object.path):len(v.object.path)], key)
+		value = mapTree(v.object.mapper, path, value)
+	}
+
+	v.visitor.VisitObjectField(key, value)
+}
+
+type mappedObjectFieldCounter struct {
+	object mappedObject
+	count  int
+}
+
+func (c *mappedObjectFieldCounter) VisitObjectField(key string, value Value) {
+	if _, _, keep := c.object.mapper.mapField(c.object.path, key, value); keep {
+		c.count++
+	}
+}
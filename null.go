@@ -0,0 +1,176 @@
+package valf
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Null returns a new Value which explicitly has no value, as opposed to
+// an empty Value of TypeNone which denotes that the value is not present.
+func Null() Value {
+	return Value{bits: bits(TypeNull) | bitsConst}
+}
+
+// BoolPtr returns a new Value with the value pointed to by v or Null if v is nil.
+func BoolPtr(v *bool) Value {
+	if v == nil {
+		return Null()
+	}
+
+	return Bool(*v)
+}
+
+// IntPtr returns a new Value with the value pointed to by v or Null if v is nil.
+func IntPtr(v *int) Value {
+	if v == nil {
+		return Null()
+	}
+
+	return Int(*v)
+}
+
+// Int64Ptr returns a new Value with the value pointed to by v or Null if v is nil.
+func Int64Ptr(v *int64) Value {
+	if v == nil {
+		return Null()
+	}
+
+	return Int64(*v)
+}
+
+// Uint64Ptr returns a new Value with the value pointed to by v or Null if v is nil.
+func Uint64Ptr(v *uint64) Value {
+	if v == nil {
+		return Null()
+	}
+
+	return Uint64(*v)
+}
+
+// Float64Ptr returns a new Value with the value pointed to by v or Null if v is nil.
+func Float64Ptr(v *float64) Value {
+	if v == nil {
+		return Null()
+	}
+
+	return Float64(*v)
+}
+
+// StringPtr returns a new Value with the value pointed to by v or Null if v is nil.
+func StringPtr(v *string) Value {
+	if v == nil {
+		return Null()
+	}
+
+	return String(*v)
+}
+
+// DurationPtr returns a new Value with the value pointed to by v or Null if v is nil.
+func DurationPtr(v *time.Duration) Value {
+	if v == nil {
+		return Null()
+	}
+
+	return Duration(*v)
+}
+
+// TimePtr returns a new Value with the value pointed to by v or Null if v is nil.
+func TimePtr(v *time.Time) Value {
+	if v == nil {
+		return Null()
+	}
+
+	return Time(*v)
+}
+
+// IsNull reports whether v is Null or holds a nil reference, i.e. a nil
+// Any, error, array, object, Stringer or big number. Annotations are ignored.
+// A Value of TypeNone is not null, it is absent.
+func (v Value) IsNull() bool {
+	v = v.Unannotated()
+
+	switch v.bits.Type() {
+	case TypeNull:
+		return true
+	case TypeAny, TypeError, TypeArray, TypeObject, TypeStringer,
+		TypeBigInt, TypeBigFloat, TypeBigRat, TypeDecimal:
+		return v.vAny == nil
+	}
+
+	return false
+}
+
+// IsZero reports whether v is absent, null or holds a value considered empty
+// by the omitempty option of encoding/json, i.e. false, zero number, zero
+// duration, empty string, empty slice, array or object. Zero time is
+// considered empty as well. Annotations are ignored and lazy values are
+// never considered empty since checking them requires their evaluation.
+func (v Value) IsZero() bool {
+	v = v.Unannotated()
+	if v.IsNull() {
+		return true
+	}
+
+	switch v.bits.Type() {
+	case TypeNone:
+		return true
+	case TypeBool, TypeInt, TypeInt8, TypeInt16, TypeInt32, TypeInt64,
+		TypeUint, TypeUint8, TypeUint16, TypeUint32, TypeUint64,
+		TypeDuration, TypeUintptr:
+		return v.vInt == 0
+	case TypeFloat32:
+		// Negative zero has different bits.
+		return math.Float32frombits(uint32(v.vInt)) == 0
+	case TypeFloat64:
+		return math.Float64frombits(uint64(v.vInt)) == 0
+	case TypeTime:
+		return v.vInt == zeroTimeUnixNano
+	case TypeString:
+		return v.vString == ""
+	case TypeStrings:
+		return len(v.vAny.([]string)) == 0
+	case TypeTimes:
+		return len(v.vAny.([]time.Time)) == 0
+	case TypeErrors:
+		return len(v.vAny.([]error)) == 0
+	case TypeStringers:
+		return len(v.vAny.([]fmt.Stringer)) == 0
+	case TypeBytes, TypeBools, TypeInts, TypeInts8, TypeInts16, TypeInts32, TypeInts64,
+		TypeUints, TypeUints8, TypeUints16, TypeUints32, TypeUints64,
+		TypeFloats32, TypeFloats64, TypeDurations,
+		TypeComplexes64, TypeComplexes128, TypeUintptrs, TypeMAC:
+		return len(v.vBytes) == 0
+	case TypeArray:
+		return v.vAny.(ValueArray).ArrayItemCount() == 0
+	case TypeObject:
+		return v.vAny.(ValueObject).ObjectFieldCount() == 0
+	}
+
+	return false
+}
+
+// zeroTimeUnixNano is what time.Time{}.UnixNano() returns since the zero
+// time is out of the range of int64 nanoseconds.
+var zeroTimeUnixNano = time.Time{}.UnixNano()
+
+// OmitNull returns a Value which omits object fields with null or absent
+// values everywhere in v, see Value.IsNull.
+func OmitNull(v Value) Value {
+	return Transformer{}.DropNulls().Apply(v)
+}
+
+// OmitEmpty returns a Value which omits object fields with empty values
+// everywhere in v like the omitempty option of encoding/json does, see
+// Value.IsZero.
+func OmitEmpty(v Value) Value {
+	return Transformer{}.DropEmpty().Apply(v)
+}
+
+func (v Value) acceptNullVisitor(visitor Visitor) {
+	if nv, ok := visitor.(NullVisitor); ok {
+		nv.VisitNull()
+	} else {
+		visitor.VisitAny(nil)
+	}
+}
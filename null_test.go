@@ -0,0 +1,124 @@
+package valf
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockNullVisitor struct {
+	mockVisitor
+	visited bool
+}
+
+func newMockNullVisitor(t *testing.T) *mockNullVisitor {
+	return &mockNullVisitor{mockVisitor: mockVisitor{t}}
+}
+
+func (v *mockNullVisitor) VisitNull() {
+	v.visited = true
+}
+
+func TestValueNull(t *testing.T) {
+	value := Null()
+	require.Equal(t, TypeNull, value.Type())
+	require.Equal(t, true, value.Const())
+	require.Equal(t, value, value.Snapshot())
+
+	visitor := newMockNullVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+
+	anyVisitor := newMockAnyVisitor(t)
+	value.AcceptVisitor(anyVisitor)
+	require.Equal(t, true, anyVisitor.visited)
+	require.Equal(t, nil, anyVisitor.value)
+}
+
+func TestValuePtr(t *testing.T) {
+	b, i, i64, u64, f64, s, d, tm := true, 1, int64(2), uint64(3), 4.5, "s", time.Second, time.Unix(5, 0)
+
+	require.Equal(t, Bool(b), BoolPtr(&b))
+	require.Equal(t, Int(i), IntPtr(&i))
+	require.Equal(t, Int64(i64), Int64Ptr(&i64))
+	require.Equal(t, Uint64(u64), Uint64Ptr(&u64))
+	require.Equal(t, Float64(f64), Float64Ptr(&f64))
+	require.Equal(t, String(s), StringPtr(&s))
+	require.Equal(t, Duration(d), DurationPtr(&d))
+	require.Equal(t, Time(tm), TimePtr(&tm))
+
+	require.Equal(t, Null(), BoolPtr(nil))
+	require.Equal(t, Null(), IntPtr(nil))
+	require.Equal(t, Null(), Int64Ptr(nil))
+	require.Equal(t, Null(), Uint64Ptr(nil))
+	require.Equal(t, Null(), Float64Ptr(nil))
+	require.Equal(t, Null(), StringPtr(nil))
+	require.Equal(t, Null(), DurationPtr(nil))
+	require.Equal(t, Null(), TimePtr(nil))
+
+	require.Equal(t, Int(i), Any(&i))
+	require.Equal(t, String(s), ConstAny(&s))
+	require.Equal(t, Null(), Any((*string)(nil)))
+}
+
+func TestValueIsNull(t *testing.T) {
+	for _, v := range []Value{
+		Null(), Any(nil), Error(nil), Array(nil), Object(nil), Stringer(nil), BigInt(nil),
+		StringPtr(nil), Annotated(Null(), Label("x")),
+	} {
+		require.Equal(t, true, v.IsNull(), v.Type())
+		require.Equal(t, true, v.IsZero(), v.Type())
+	}
+	for _, v := range []Value{
+		{}, Int(0), String(""), Error(errors.New("failure")), Array(mockArray{}),
+	} {
+		require.Equal(t, false, v.IsNull(), v.Type())
+	}
+}
+
+func TestValueIsZero(t *testing.T) {
+	for _, v := range []Value{
+		{}, Bool(false), Int(0), Uint8(0), Float64(0), Float64(math.Copysign(0, -1)),
+		Float32(float32(math.Copysign(0, -1))), Duration(0), String(""), Strings(nil),
+		Bytes([]byte{}), Ints(nil), Time(time.Time{}), Array(mockArray{}), Object(mockObject{}),
+		Annotated(Int(0), Unit("ms")),
+	} {
+		require.Equal(t, true, v.IsZero(), v.Type())
+	}
+	for _, v := range []Value{
+		Bool(true), Int(1), Float64(0.5), Float32(-0.5), String("a"), Ints([]int{0}), Time(time.Unix(0, 0)),
+		Array(mockArray{Int(0)}), Object(mockObject{"a": Null()}), Lazy(func() Value { return Null() }),
+	} {
+		require.Equal(t, false, v.IsZero(), v.Type())
+	}
+}
+
+func TestOmitNull(t *testing.T) {
+	visitor := newMockObjectVisitor(t)
+	OmitNull(Object(mockObject{
+		"a": Int(0),
+		"b": Null(),
+		"c": Value{},
+		"d": Object(mockObject{"e": Error(nil), "f": String("")}),
+	})).AcceptVisitor(visitor)
+	require.Equal(t, 2, visitor.count)
+	require.Equal(t, Int(0), visitor.value["a"])
+
+	nested := newMockObjectVisitor(t)
+	visitor.value["d"].AcceptVisitor(nested)
+	require.Equal(t, map[string]Value{"f": String("")}, nested.value)
+}
+
+func TestOmitEmpty(t *testing.T) {
+	visitor := newMockObjectVisitor(t)
+	OmitEmpty(Object(mockObject{
+		"a": Int(0),
+		"b": Null(),
+		"c": String("c"),
+		"d": Strings([]string{}),
+	})).AcceptVisitor(visitor)
+	require.Equal(t, map[string]Value{"c": String("c")}, visitor.value)
+}
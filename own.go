@@ -0,0 +1,31 @@
+package valf
+
+// bitsOwned marks values passed to Own whose data checksum is stored in
+// the owned field, it is used only in builds with the valfdebug tag.
+// The flag does not interfere with IP families stored in the same byte.
+const bitsOwned bits = 1 << (bitsFlagsShift + 7)
+
+// Own returns v marked as const without copying its data, so that Snapshot
+// does not copy it either. By calling Own the caller transfers the ownership
+// of all memory referenced by v and guarantees that it is never modified
+// afterwards, e.g. that a buffer is not reused. It allows to log values
+// asynchronously without copying them.
+//
+// Lazy values are returned as is since they can be evaluated only by
+// Snapshot. Annotated values own their inner value.
+//
+// In builds with the valfdebug tag Own records a checksum of bytes, typed
+// slices, strings slices and times slices, and AcceptVisitor panics if the
+// data were modified after the call.
+func Own(v Value) Value {
+	switch {
+	case v.bits.Const(), v.bits.Type() == TypeLazy:
+		return v
+	case v.bits.Type() == TypeAnnotated:
+		return reannotate(v, Own(v.Unannotated()))
+	}
+
+	v.bits |= bitsConst
+
+	return markOwned(v)
+}
@@ -0,0 +1,62 @@
+//go:build valfdebug
+
+package valf
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// ownership keeps a checksum of the data owned by a value.
+type ownership struct {
+	checksum uint64
+}
+
+// markOwned stores a checksum of the data owned by v so that checkOwned
+// can detect modifications of the data.
+func markOwned(v Value) Value {
+	if sum, ok := ownedChecksum(v); ok {
+		v.owned.checksum = sum
+		v.bits |= bitsOwned
+	}
+
+	return v
+}
+
+// checkOwned panics if the data owned by v were modified after Own.
+func checkOwned(v Value) {
+	if v.bits&bitsOwned == 0 {
+		return
+	}
+	if sum, _ := ownedChecksum(v); sum != v.owned.checksum {
+		panic("valf: data of owned value of type " + strconv.Itoa(int(v.bits.Type())) + " were modified after Own")
+	}
+}
+
+func ownedChecksum(v Value) (uint64, bool) {
+	h := fnv.New64a()
+
+	switch t := v.bits.Type(); t {
+	case TypeStrings:
+		for _, s := range v.vAny.([]string) {
+			h.Write([]byte(s))
+			h.Write([]byte{0})
+		}
+	case TypeTimes:
+		for _, tm := range v.vAny.([]time.Time) {
+			h.Write([]byte(tm.Format(time.RFC3339Nano)))
+		}
+	default:
+		size := itemSize(t)
+		if size == 0 {
+			return 0, false
+		}
+		if len(v.vBytes) != 0 {
+			h.Write(unsafe.Slice(&v.vBytes[0], len(v.vBytes)*size))
+		}
+	}
+
+	return h.Sum64(), true
+}
@@ -0,0 +1,37 @@
+//go:build valfdebug
+
+package valf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnDebug(t *testing.T) {
+	v := []int{1, 2}
+	value := Own(Ints(v))
+	require.NotPanics(t, func() {
+		value.AcceptVisitor(IgnoringVisitor{})
+	})
+
+	v[0] = 42
+	require.Panics(t, func() {
+		value.AcceptVisitor(IgnoringVisitor{})
+	})
+
+	s := []string{"a"}
+	value = Own(Strings(s))
+	s[0] = "b"
+	require.Panics(t, func() {
+		value.AcceptVisitor(IgnoringVisitor{})
+	})
+
+	tm := []time.Time{time.Unix(1, 0)}
+	value = Own(Times(tm))
+	tm[0] = time.Unix(2, 0)
+	require.Panics(t, func() {
+		value.AcceptVisitor(IgnoringVisitor{})
+	})
+}
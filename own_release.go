@@ -0,0 +1,12 @@
+//go:build !valfdebug
+
+package valf
+
+// ownership takes no space in builds without the valfdebug tag.
+type ownership struct{}
+
+func markOwned(v Value) Value {
+	return v
+}
+
+func checkOwned(Value) {}
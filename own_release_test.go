@@ -0,0 +1,18 @@
+//go:build !valfdebug
+
+package valf
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueSize(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("the size is checked on 64-bit platforms only")
+	}
+
+	require.Equal(t, uintptr(80), unsafe.Sizeof(Value{}))
+}
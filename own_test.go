@@ -0,0 +1,27 @@
+package valf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwn(t *testing.T) {
+	v := []int{1, 2}
+	value := Own(Ints(v))
+	require.Equal(t, true, value.Const())
+	require.Equal(t, TypeInts, value.Type())
+
+	snapshot := value.Snapshot()
+	visitor := newMockIntsVisitor(t)
+	snapshot.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	lazy := Lazy(func() Value { return Int(1) })
+	require.Equal(t, false, Own(lazy).Const())
+	require.Equal(t, Int(1), Own(Int(1)))
+
+	annotated := Own(Annotated(Strings([]string{"a"}), Label("x")))
+	require.Equal(t, true, annotated.Const())
+	require.Equal(t, true, annotated.Unannotated().Const())
+}
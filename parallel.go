@@ -0,0 +1,128 @@
+package valf
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelism limits the number of goroutines used to snapshot large
+// arrays and objects. Tokens are shared by all nesting levels, so nested
+// arrays are snapshotted in parallel only if there are idle workers.
+type parallelism struct {
+	threshold int
+	workers   int
+	tokens    chan struct{}
+}
+
+func newParallelism(threshold, maxWorkers int) *parallelism {
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.GOMAXPROCS(0) - 1
+	}
+	if maxWorkers <= 0 {
+		return nil
+	}
+
+	return &parallelism{threshold, maxWorkers, make(chan struct{}, maxWorkers)}
+}
+
+// accepts reports whether n items of the array or object should be
+// snapshotted in parallel after they are visited. Items of arrays and
+// objects not implementing StableItems may reference memory reused between
+// visits, so they are snapshotted while being visited.
+func (p *parallelism) accepts(container interface{}, n int) bool {
+	if p == nil || n < p.threshold {
+		return false
+	}
+	_, ok := container.(StableItems)
+
+	return ok
+}
+
+// run calls fn for each index in [0, n) using the calling goroutine and as
+// many idle workers as available and returns when all calls are done.
+// Workers get contexts without the arena since it is not safe for concurrent
+// use. A panic in any worker is propagated to the calling goroutine.
+func (p *parallelism) run(c snapshotContext, n int, fn func(c snapshotContext, i int)) {
+	chunk := (n + 4*(p.workers+1) - 1) / (4 * (p.workers + 1))
+	chunks := (n + chunk - 1) / chunk
+	next := int64(-1)
+
+	work := func(c snapshotContext) {
+		for {
+			i := int(atomic.AddInt64(&next, 1))
+			if i >= chunks {
+				return
+			}
+			end := (i + 1) * chunk
+			if end > n {
+				end = n
+			}
+			for j := i * chunk; j != end; j++ {
+				fn(c, j)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var allocated int
+	var recovered interface{}
+
+	// Workers never outlive the call even if the calling goroutine panics.
+	defer func() {
+		atomic.StoreInt64(&next, int64(chunks))
+		wg.Wait()
+	}()
+
+	for w := 1; w < chunks && p.acquire(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer p.release()
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					recovered = r
+					mu.Unlock()
+					atomic.StoreInt64(&next, int64(chunks))
+				}
+			}()
+
+			wc := c
+			wc.arena = nil
+			var local int
+			if c.allocated != nil {
+				wc.allocated = &local
+			}
+			work(wc)
+
+			mu.Lock()
+			allocated += local
+			mu.Unlock()
+		}()
+	}
+
+	work(c)
+	wg.Wait()
+
+	if recovered != nil {
+		panic(recovered)
+	}
+	if c.allocated != nil {
+		*c.allocated += allocated
+	}
+}
+
+func (p *parallelism) acquire() bool {
+	select {
+	case p.tokens <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *parallelism) release() {
+	<-p.tokens
+}
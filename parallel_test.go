@@ -0,0 +1,186 @@
+package valf
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testParallelRecord(n int) Value {
+	items := make([]Value, n)
+	for i := range items {
+		nested := make([]Value, n/10)
+		for j := range nested {
+			nested[j] = Ints([]int{i, j})
+		}
+		items[i] = Object(FieldsOf(
+			Field{fmt.Sprint("item", i%7), Values(nested)},
+			Field{"buffer", Array(testBufferArray(n / 10))},
+		))
+		if i%3 == 0 {
+			i := i
+			items[i] = Lazy(func() Value { return Strings([]string{fmt.Sprint(i)}) })
+		}
+	}
+
+	return Values(items)
+}
+
+// testBufferArray reuses a single buffer for all its items.
+type testBufferArray int
+
+func (a testBufferArray) ArrayItemCount() int {
+	return int(a)
+}
+
+func (a testBufferArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	buf := []int{0}
+	nested := []Value{Int(0)}
+	for i := 0; i != int(a); i++ {
+		buf[0] = i
+		nested[0] = Ints(buf)
+		visitor.VisitArrayItem(i, Values(nested))
+	}
+}
+
+func TestSnapshotParallel(t *testing.T) {
+	expected := testParallelRecord(200)
+	allocated := SnapshotWith(&expected, SnapshotOptions{})
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+			value := testParallelRecord(200)
+			options := SnapshotOptions{ParallelThreshold: 10, MaxWorkers: workers}
+			require.Equal(t, allocated, SnapshotWith(&value, options))
+			require.Equal(t, expected, value)
+			require.Equal(t, true, value.Const())
+		})
+	}
+}
+
+func TestSnapshotParallelBuffer(t *testing.T) {
+	expected := Array(testBufferArray(100))
+	SnapshotWith(&expected, SnapshotOptions{})
+
+	visitor := newMockArrayVisitor(t)
+	expected.AcceptVisitor(visitor)
+	require.Len(t, visitor.value, 100)
+	require.Equal(t, Values([]Value{Ints([]int{3})}).Snapshot(), visitor.value[3])
+
+	value := Array(testBufferArray(100))
+	SnapshotWith(&value, SnapshotOptions{ParallelThreshold: 10, MaxWorkers: 4})
+	require.Equal(t, expected, value)
+}
+
+func TestSnapshotParallelObject(t *testing.T) {
+	fields := MakeFields(100)
+	for i := 0; i != 100; i++ {
+		fields.Add(fmt.Sprint("field", i), Ints([]int{i}))
+	}
+
+	value := Object(fields)
+	SnapshotWith(&value, SnapshotOptions{ParallelThreshold: 10, MaxWorkers: 4})
+
+	visitor := newMockObjectVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Len(t, visitor.value, 100)
+	for i := 0; i != 100; i++ {
+		require.Equal(t, Ints([]int{i}).Snapshot(), visitor.value[fmt.Sprint("field", i)])
+	}
+}
+
+func TestSnapshotParallelBelowThreshold(t *testing.T) {
+	var calls int32
+	items := make([]Value, 5)
+	for i := range items {
+		items[i] = Lazy(func() Value {
+			atomic.AddInt32(&calls, 1)
+
+			return Int(1)
+		})
+	}
+
+	value := Values(items)
+	SnapshotWith(&value, SnapshotOptions{ParallelThreshold: 10})
+	require.Equal(t, int32(5), calls)
+	require.Equal(t, Values([]Value{Int(1), Int(1), Int(1), Int(1), Int(1)}).Snapshot(), value)
+}
+
+// testStableArray is a user array which opts in to parallel snapshotting.
+type testStableArray []Value
+
+func (a testStableArray) ArrayItemCount() int {
+	return len(a)
+}
+
+func (a testStableArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i, item := range a {
+		visitor.VisitArrayItem(i, item)
+	}
+}
+
+func (a testStableArray) StableItems() {}
+
+func TestSnapshotParallelStableItems(t *testing.T) {
+	// Each lazy item waits until another one is evaluated concurrently,
+	// which is possible only if the array is snapshotted in parallel.
+	var active int32
+	concurrent := make(chan struct{})
+	var once sync.Once
+	var timeouts int32
+	items := make(testStableArray, 100)
+	for i := range items {
+		i := i
+		items[i] = Lazy(func() Value {
+			if atomic.AddInt32(&active, 1) == 2 {
+				once.Do(func() { close(concurrent) })
+			}
+			defer atomic.AddInt32(&active, -1)
+
+			select {
+			case <-concurrent:
+			case <-time.After(time.Second):
+				atomic.AddInt32(&timeouts, 1)
+				once.Do(func() { close(concurrent) })
+			}
+
+			return Int(i)
+		})
+	}
+
+	value := Array(items)
+	SnapshotWith(&value, SnapshotOptions{ParallelThreshold: 10, MaxWorkers: 4})
+	require.Equal(t, int32(0), atomic.LoadInt32(&timeouts))
+
+	visitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Len(t, visitor.value, 100)
+	require.Equal(t, Int(42), visitor.value[42])
+}
+
+type testPanickingArray struct{}
+
+func (testPanickingArray) ArrayItemCount() int {
+	return 1
+}
+
+func (testPanickingArray) AcceptArrayItemVisitor(ArrayItemVisitor) {
+	panic("failure")
+}
+
+func TestSnapshotParallelPanic(t *testing.T) {
+	items := make([]Value, 100)
+	for i := range items {
+		items[i] = Int(i)
+	}
+	items[99] = Array(testPanickingArray{})
+
+	value := Values(items)
+	require.Panics(t, func() {
+		SnapshotWith(&value, SnapshotOptions{ParallelThreshold: 10, MaxWorkers: 4})
+	})
+}
@@ -0,0 +1,285 @@
+package valf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"unicode/utf8"
+)
+
+// Encoding identifies the format of raw pre-encoded data.
+type Encoding byte
+
+// Valid values for Encoding.
+const (
+	// EncodingBinary is opaque binary data with no known format.
+	EncodingBinary Encoding = iota
+	// EncodingText is UTF-8 text.
+	EncodingText
+	// EncodingJSON is a single JSON value.
+	EncodingJSON
+	// EncodingMsgPack is a single MessagePack value.
+	EncodingMsgPack
+	// EncodingCBOR is a single CBOR data item.
+	EncodingCBOR
+)
+
+// String returns the name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case EncodingBinary:
+		return "binary"
+	case EncodingText:
+		return "text"
+	case EncodingJSON:
+		return "json"
+	case EncodingMsgPack:
+		return "msgpack"
+	case EncodingCBOR:
+		return "cbor"
+	}
+
+	return fmt.Sprintf("Encoding(%d)", byte(e))
+}
+
+// Validate returns an error if data is not well-formed according to the
+// encoding. Only text and JSON encodings are checked, data in other
+// encodings is always considered valid.
+func (e Encoding) Validate(data []byte) error {
+	switch e {
+	case EncodingText:
+		if !utf8.Valid(data) {
+			return errors.New("valf: raw text is not valid UTF-8")
+		}
+	case EncodingJSON:
+		if !json.Valid(data) {
+			return errors.New("valf: raw JSON is not valid")
+		}
+	}
+
+	return nil
+}
+
+// Raw returns a new Value with the given data pre-encoded with the given encoding.
+// Visitors implementing RawVisitor may embed the data verbatim if the encoding
+// matches their output format. Other visitors get JSON data decoded, text data
+// as a string and data in any other encoding as bytes.
+//
+// The data is not validated, use Encoding.Validate or ValidRaw if it comes
+// from an untrusted source. JSON data of snapshotted values is decoded for
+// other visitors at most once.
+func Raw(enc Encoding, data []byte) Value {
+	if data == nil {
+		return ConstRaw(enc, data)
+	}
+
+	return Value{bits: bits(TypeRaw), vInt: int64(enc), vBytes: data}
+}
+
+// ConstRaw returns a new Value with the given data pre-encoded with the given encoding.
+//
+// Call ConstRaw if your data is const. It has significantly less impact
+// on the calling goroutine.
+func ConstRaw(enc Encoding, data []byte) Value {
+	return Value{bits: bits(TypeRaw) | bitsConst, vInt: int64(enc), vBytes: data}
+}
+
+// ValidRaw returns a new Value with the given data pre-encoded with the given
+// encoding or an error if the data is not well-formed, see Encoding.Validate.
+func ValidRaw(enc Encoding, data []byte) (Value, error) {
+	if err := enc.Validate(data); err != nil {
+		return Value{}, err
+	}
+
+	return Raw(enc, data), nil
+}
+
+// RawJSON returns a new Value with the given JSON data, see Raw.
+func RawJSON(data []byte) Value {
+	return Raw(EncodingJSON, data)
+}
+
+// ConstRawJSON returns a new Value with the given JSON data, see ConstRaw.
+func ConstRawJSON(data []byte) Value {
+	return ConstRaw(EncodingJSON, data)
+}
+
+// RawText returns a new Value with the given UTF-8 text, see Raw.
+func RawText(data []byte) Value {
+	return Raw(EncodingText, data)
+}
+
+// ConstRawText returns a new Value with the given UTF-8 text, see ConstRaw.
+func ConstRawText(data []byte) Value {
+	return ConstRaw(EncodingText, data)
+}
+
+func (v Value) acceptRawVisitor(visitor Visitor) {
+	enc := Encoding(v.vInt)
+	if rv, ok := visitor.(RawVisitor); ok {
+		rv.VisitRaw(enc, v.vBytes)
+
+		return
+	}
+
+	switch enc {
+	case EncodingText:
+		visitor.VisitString(string(v.vBytes))
+	case EncodingJSON:
+		if decoded, err := v.decodeRawJSON(); err == nil {
+			decoded.AcceptVisitor(visitor)
+		} else {
+			visitor.VisitString(string(v.vBytes))
+		}
+	default:
+		visitor.VisitBytes(v.vBytes)
+	}
+}
+
+// MaxJSONDepth is the maximum nesting depth of arrays and objects in raw
+// JSON data decoded for visitors that do not implement RawVisitor.
+// Deeper data is visited as a string.
+const MaxJSONDepth = 100
+
+// rawJSON keeps JSON data of a snapshotted raw value decoded once.
+// It is attached by snapshotRaw, so that creating raw values never allocates.
+type rawJSON struct {
+	once    sync.Once
+	decoded Value
+	err     error
+}
+
+// decodeRawJSON decodes JSON data of a raw value. Data of snapshotted values
+// is decoded once, other values are decoded each time.
+func (v Value) decodeRawJSON() (Value, error) {
+	c, ok := v.vAny.(*rawJSON)
+	if !ok {
+		return decodeRawJSON(v.vBytes)
+	}
+
+	c.once.Do(func() {
+		c.decoded, c.err = decodeRawJSON(v.vBytes)
+	})
+
+	return c.decoded, c.err
+}
+
+// decodeRawJSON decodes data to a const Value keeping the order of object fields.
+// Integers which do not fit in int64 are decoded as big integers.
+func decodeRawJSON(data []byte) (Value, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	v, err := decodeJSONValue(d, 0)
+	if err != nil {
+		return Value{}, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return Value{}, errors.New("valf: unexpected data after JSON value")
+	}
+
+	return v, nil
+}
+
+func decodeJSONValue(d *json.Decoder, depth int) (Value, error) {
+	token, err := d.Token()
+	if err != nil {
+		return Value{}, err
+	}
+
+	switch t := token.(type) {
+	case nil:
+		return ConstAny(nil), nil
+	case bool:
+		return Bool(t), nil
+	case string:
+		return String(t), nil
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return Int64(i), nil
+		}
+		// Integers which do not fit in int64 would lose precision as floats.
+		if i, ok := new(big.Int).SetString(t.String(), 10); ok {
+			return ConstBigInt(i), nil
+		}
+		if f, err := t.Float64(); err == nil {
+			return Float64(f), nil
+		}
+
+		return String(t.String()), nil
+	case json.Delim:
+		if depth == MaxJSONDepth {
+			return Value{}, errors.New("valf: raw JSON is nested too deeply")
+		}
+
+		switch t {
+		case '[':
+			items := jsonArray{}
+			for d.More() {
+				item, err := decodeJSONValue(d, depth+1)
+				if err != nil {
+					return Value{}, err
+				}
+				items = append(items, item)
+			}
+			if _, err := d.Token(); err != nil {
+				return Value{}, err
+			}
+
+			return ConstArray(items), nil
+		case '{':
+			fields := jsonObject{}
+			for d.More() {
+				key, err := d.Token()
+				if err != nil {
+					return Value{}, err
+				}
+				value, err := decodeJSONValue(d, depth+1)
+				if err != nil {
+					return Value{}, err
+				}
+				fields = append(fields, jsonField{key.(string), value})
+			}
+			if _, err := d.Token(); err != nil {
+				return Value{}, err
+			}
+
+			return ConstObject(fields), nil
+		}
+	}
+
+	return Value{}, fmt.Errorf("valf: unexpected JSON token %v", token)
+}
+
+type jsonArray []Value
+
+func (a jsonArray) ArrayItemCount() int {
+	return len(a)
+}
+
+func (a jsonArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i, item := range a {
+		visitor.VisitArrayItem(i, item)
+	}
+}
+
+type jsonField struct {
+	key   string
+	value Value
+}
+
+type jsonObject []jsonField
+
+func (o jsonObject) ObjectFieldCount() int {
+	return len(o)
+}
+
+func (o jsonObject) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	for _, field := range o {
+		visitor.VisitObjectField(field.key, field.value)
+	}
+}
@@ -0,0 +1,162 @@
+package valf
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockRawVisitor struct {
+	mockVisitor
+	encoding Encoding
+	value    []byte
+	visited  bool
+}
+
+func newMockRawVisitor(t *testing.T) *mockRawVisitor {
+	return &mockRawVisitor{mockVisitor: mockVisitor{t}}
+}
+
+func (v *mockRawVisitor) VisitRaw(encoding Encoding, value []byte) {
+	v.encoding = encoding
+	v.value = value
+	v.visited = true
+}
+
+func TestValueRaw(t *testing.T) {
+	data := []byte{0x81, 0xa1, 0x61, 0x01}
+	value := Raw(EncodingMsgPack, data)
+	require.Equal(t, TypeRaw, value.Type())
+	require.Equal(t, false, value.Const())
+	require.Equal(t, true, ConstRaw(EncodingMsgPack, data).Const())
+	require.Equal(t, true, Raw(EncodingMsgPack, nil).Const())
+
+	visitor := newMockRawVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, EncodingMsgPack, visitor.encoding)
+	require.Equal(t, data, visitor.value)
+
+	bytesVisitor := newMockBytesVisitor(t)
+	value.AcceptVisitor(bytesVisitor)
+	require.Equal(t, data, bytesVisitor.value)
+}
+
+func TestValueRawText(t *testing.T) {
+	value := RawText([]byte("hello"))
+
+	visitor := newMockRawVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, EncodingText, visitor.encoding)
+	require.Equal(t, []byte("hello"), visitor.value)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "hello", stringVisitor.value)
+}
+
+func TestValueRawJSON(t *testing.T) {
+	value := RawJSON([]byte(`{"b": [1, 2.5, "x"], "a": {"c": null, "d": true}}`))
+
+	visitor := newOrderedObjectVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, 2, visitor.count)
+	require.Equal(t, []string{"b", "a"}, visitor.keys)
+
+	arrayVisitor := newMockArrayVisitor(t)
+	visitor.value["b"].AcceptVisitor(arrayVisitor)
+	require.Equal(t, []Value{Int64(1), Float64(2.5), String("x")}, arrayVisitor.value)
+
+	bigVisitor := newMockBigVisitor(t)
+	RawJSON([]byte(`12345678901234567890123`)).AcceptVisitor(bigVisitor)
+	require.Equal(t, true, bigVisitor.visited)
+	expected, _ := new(big.Int).SetString("12345678901234567890123", 10)
+	require.Equal(t, expected, bigVisitor.value)
+
+	floatVisitor := newMockFloat64Visitor(t)
+	RawJSON([]byte(`1.2345678901234567890123e22`)).AcceptVisitor(floatVisitor)
+	require.Equal(t, 1.2345678901234568e+22, floatVisitor.value)
+
+	objectVisitor := newMockObjectVisitor(t)
+	visitor.value["a"].AcceptVisitor(objectVisitor)
+	require.Equal(t, map[string]Value{"c": ConstAny(nil), "d": Bool(true)}, objectVisitor.value)
+
+	message := json.RawMessage(`42`)
+	require.Equal(t, RawJSON(message), Any(message))
+	require.Equal(t, ConstRawJSON(message), ConstAny(message))
+}
+
+func TestValueRawInvalidJSON(t *testing.T) {
+	for _, data := range []string{`{"a": `, `1 2`, `}`} {
+		stringVisitor := newMockStringVisitor(t)
+		RawJSON([]byte(data)).AcceptVisitor(stringVisitor)
+		require.Equal(t, data, stringVisitor.value)
+	}
+}
+
+func TestValueRawJSONDecodedOnce(t *testing.T) {
+	data := []byte(`{"a": 1}`)
+	value := RawJSON(data).Snapshot()
+	first := newMockObjectVisitor(t)
+	value.AcceptVisitor(first)
+	require.Equal(t, map[string]Value{"a": Int64(1)}, first.value)
+
+	cache := value.vAny.(*rawJSON)
+	decoded := cache.decoded
+
+	second := newMockObjectVisitor(t)
+	value.AcceptVisitor(second)
+	require.Equal(t, first.value, second.value)
+	require.Equal(t, decoded, cache.decoded)
+
+	// Creating raw values does not allocate the cache.
+	var message interface{} = json.RawMessage(data)
+	require.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+		valueSink = ConstRawJSON(data)
+		valueSink = ConstAny(message)
+	}))
+
+	// Data of non-const values may change between visits.
+	value = RawJSON(data)
+	copy(data, `{"b"`)
+	visitor := newMockObjectVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, map[string]Value{"b": Int64(1)}, visitor.value)
+}
+
+func TestValueRawJSONDepth(t *testing.T) {
+	nested := func(depth int) []byte {
+		return []byte(strings.Repeat("[", depth) + strings.Repeat("]", depth))
+	}
+
+	arrayVisitor := newMockArrayVisitor(t)
+	RawJSON(nested(MaxJSONDepth)).AcceptVisitor(arrayVisitor)
+	require.Equal(t, true, arrayVisitor.visited)
+
+	data := nested(MaxJSONDepth + 1)
+	stringVisitor := newMockStringVisitor(t)
+	ConstRawJSON(data).AcceptVisitor(stringVisitor)
+	require.Equal(t, string(data), stringVisitor.value)
+}
+
+func TestEncodingValidate(t *testing.T) {
+	require.NoError(t, EncodingJSON.Validate([]byte(`{"a": 1}`)))
+	require.Error(t, EncodingJSON.Validate([]byte(`{"a": }`)))
+	require.NoError(t, EncodingText.Validate([]byte("hello")))
+	require.Error(t, EncodingText.Validate([]byte{0xff}))
+	require.NoError(t, EncodingMsgPack.Validate([]byte{0xc1}))
+
+	value, err := ValidRaw(EncodingJSON, []byte(`[]`))
+	require.NoError(t, err)
+	require.Equal(t, RawJSON([]byte(`[]`)), value)
+
+	_, err = ValidRaw(EncodingJSON, []byte(`[`))
+	require.Error(t, err)
+
+	require.Equal(t, "json", EncodingJSON.String())
+	require.Equal(t, "Encoding(42)", Encoding(42).String())
+}
@@ -0,0 +1,357 @@
+package valf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMask is the replacement used for sensitive fields declared by SensitiveObject.
+const DefaultMask = "********"
+
+// Masker produces a replacement for a sensitive value.
+type Masker func(Value) Value
+
+// MaskWith returns a Masker which replaces a value with the given string.
+func MaskWith(replacement string) Masker {
+	return func(Value) Value {
+		return String(replacement)
+	}
+}
+
+// MaskHash returns a Masker which replaces a value with a truncated
+// HMAC-SHA-256 of its text representation computed with the given key.
+// It allows to correlate equal values without revealing them. The key must
+// be kept secret, otherwise low-entropy values can be recovered by brute force.
+func MaskHash(key []byte) Masker {
+	key = append([]byte(nil), key...)
+
+	return func(v Value) Value {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(valueText(v)))
+		sum := h.Sum(nil)
+
+		return String("hmac-sha256:" + hex.EncodeToString(sum[:8]))
+	}
+}
+
+// MaskPartial returns a Masker which keeps the given number of leading and
+// trailing characters of a value's text representation and replaces all
+// other characters with '*'. If the text is too short to keep anything
+// hidden, it is masked completely.
+func MaskPartial(keepPrefix, keepSuffix int) Masker {
+	return func(v Value) Value {
+		s := valueText(v)
+		n := utf8.RuneCountInString(s)
+		if n <= keepPrefix+keepSuffix {
+			return String(strings.Repeat("*", n))
+		}
+
+		var b strings.Builder
+		b.Grow(len(s))
+		i := 0
+		for _, r := range s {
+			if i < keepPrefix || i >= n-keepSuffix {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte('*')
+			}
+			i++
+		}
+
+		return String(b.String())
+	}
+}
+
+// SensitiveObject is the interface that allows a ValueObject to declare its
+// own sensitive fields which are masked by any Redactor.
+type SensitiveObject interface {
+	ValueObject
+	SensitiveFields() []string
+}
+
+// RedactionRule defines which values are sensitive and how they are masked.
+type RedactionRule struct {
+	mask    Masker
+	keys    []string
+	paths   [][]string
+	types   []Type
+	pattern *regexp.Regexp
+}
+
+// RedactKeys returns a RedactionRule which masks values of object fields
+// with any of the given keys. Keys are compared case-insensitively.
+func RedactKeys(mask Masker, keys ...string) RedactionRule {
+	return RedactionRule{mask: mask, keys: keys}
+}
+
+// RedactPaths returns a RedactionRule which masks values of object fields
+// located at any of the given paths. A path is a dot-separated list of keys
+// where '*' matches any single key and '**' matches any number of keys
+// including zero, e.g. "user.password", "**.token" or "user.**".
+// Arrays do not add path segments.
+func RedactPaths(mask Masker, patterns ...string) RedactionRule {
+	paths := make([][]string, len(patterns))
+	for i, pattern := range patterns {
+		paths[i] = strings.Split(pattern, ".")
+	}
+
+	return RedactionRule{mask: mask, paths: paths}
+}
+
+// RedactTypes returns a RedactionRule which masks values of any of the given types.
+func RedactTypes(mask Masker, types ...Type) RedactionRule {
+	return RedactionRule{mask: mask, types: types}
+}
+
+// RedactPattern returns a RedactionRule which masks all parts of string
+// contents matching the given regular expression, e.g. card numbers.
+// Stringer and Formatter values are rendered to strings to be checked,
+// as well as messages of errors and items of slices of errors and Stringers.
+// Errors with matching messages are replaced with errors having the masked
+// message and the original type name but no causes or fields since they
+// are likely to contain the same data.
+func RedactPattern(mask Masker, pattern *regexp.Regexp) RedactionRule {
+	return RedactionRule{mask: mask, pattern: pattern}
+}
+
+func (r *RedactionRule) matchField(path []string, key string) bool {
+	for _, k := range r.keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	for _, p := range r.paths {
+		if matchPath(p, path, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RedactionRule) matchType(t Type) bool {
+	for _, rt := range r.types {
+		if rt == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RedactionRule) redactContents(v Value) Value {
+	switch v.bits.Type() {
+	case TypeString:
+		return r.redactString(v)
+	case TypeStringer, TypeFormatter:
+		if v.vAny != nil {
+			return r.redactString(String(valueText(v)))
+		}
+	case TypeError:
+		if v.vAny != nil {
+			err := v.vAny.(error)
+			if message := err.Error(); r.pattern.MatchString(message) {
+				return ConstError(textError(err, r.redactString(String(message)).vString))
+			}
+		}
+	case TypeStrings:
+		return r.redactStrings(v, v.vAny.([]string))
+	case TypeErrors:
+		return r.redactStrings(v, errorsText(v.vAny.([]error)))
+	case TypeStringers:
+		return r.redactStrings(v, stringersText(v.vAny.([]fmt.Stringer)))
+	}
+
+	return v
+}
+
+// redactStrings returns v as is if none of the strings s representing its
+// items match the pattern, otherwise it returns the masked strings.
+func (r *RedactionRule) redactStrings(v Value, s []string) Value {
+	for i, item := range s {
+		if r.pattern.MatchString(item) {
+			cc := make([]string, len(s))
+			copy(cc, s[:i])
+			for j := i; j != len(s); j++ {
+				cc[j] = r.redactString(String(s[j])).vString
+			}
+
+			return ConstStrings(cc)
+		}
+	}
+
+	return v
+}
+
+func (r *RedactionRule) redactString(v Value) Value {
+	if !r.pattern.MatchString(v.vString) {
+		return v
+	}
+
+	return String(r.pattern.ReplaceAllStringFunc(v.vString, func(s string) string {
+		return valueText(r.mask(String(s)))
+	}))
+}
+
+// matchPath reports whether path extended with key matches pattern.
+func matchPath(pattern []string, path []string, key string) bool {
+	return pathMatcher{pattern, path, key}.match(0, 0)
+}
+
+// pathMatcher matches a pattern against path segments followed by key.
+type pathMatcher struct {
+	pattern []string
+	path    []string
+	key     string
+}
+
+func (m pathMatcher) match(p, i int) bool {
+	n := len(m.path) + 1
+	if p == len(m.pattern) {
+		return i == n
+	}
+
+	head := m.pattern[p]
+	if head == "**" {
+		for j := i; j <= n; j++ {
+			if m.match(p+1, j) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if i == n || (head != "*" && head != m.segment(i)) {
+		return false
+	}
+
+	return m.match(p+1, i+1)
+}
+
+func (m pathMatcher) segment(i int) string {
+	if i < len(m.path) {
+		return m.path[i]
+	}
+
+	return m.key
+}
+
+// Redactor masks sensitive values of a value tree according to the rules.
+// Rules are checked in the order they were given and the first matching rule wins.
+// Raw JSON and text values are decoded to be redacted, raw values in other
+// encodings or malformed ones are masked as a whole by the first rule.
+type Redactor struct {
+	rules []RedactionRule
+}
+
+// NewRedactor returns a new Redactor with the given rules.
+func NewRedactor(rules ...RedactionRule) *Redactor {
+	return &Redactor{rules}
+}
+
+// Redact returns a Value which masks sensitive values of v lazily, i.e.
+// while it is being visited. The returned value is not const so that
+// snapshotting it never retains the unmasked data.
+func (r *Redactor) Redact(v Value) Value {
+	return mapRoot(r, v)
+}
+
+// Snapshot returns a snapshot of v with all sensitive values masked eagerly.
+func (r *Redactor) Snapshot(v Value) Value {
+	return r.Redact(v).Snapshot()
+}
+
+func (r *Redactor) mapField(path []string, key string, value Value) (string, Value, bool) {
+	return key, r.redact(path, key, true, value), true
+}
+
+func (r *Redactor) mapItem(path []string, value Value) Value {
+	return r.redact(path, "", false, value)
+}
+
+func (r *Redactor) redact(path []string, key string, field bool, value Value) Value {
+	t := value.bits.Type()
+	opaque := false
+	if t == TypeRaw && len(r.rules) != 0 {
+		value, opaque = inspectRaw(value)
+	}
+
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if (field && rule.matchField(path, key)) || rule.matchType(t) {
+			return rule.mask(value)
+		}
+		if rule.pattern != nil {
+			value = rule.redactContents(value)
+		}
+	}
+
+	if opaque {
+		// Raw data which cannot be inspected may contain anything.
+		return r.rules[0].mask(value)
+	}
+
+	if value.bits.Type() == TypeObject && value.vAny != nil {
+		if o, ok := value.vAny.(SensitiveObject); ok {
+			return Object(sensitiveObject{o, o.SensitiveFields()})
+		}
+	}
+
+	return value
+}
+
+// inspectRaw decodes raw JSON and text data so that it can be redacted
+// as any other value. It reports whether the data cannot be inspected.
+func inspectRaw(v Value) (Value, bool) {
+	switch Encoding(v.vInt) {
+	case EncodingText:
+		return String(string(v.vBytes)), false
+	case EncodingJSON:
+		if decoded, err := v.decodeRawJSON(); err == nil {
+			return decoded, false
+		}
+	}
+
+	return v, true
+}
+
+func (r *Redactor) filtersFields() bool {
+	return false
+}
+
+// sensitiveObject masks fields declared by SensitiveObject.
+type sensitiveObject struct {
+	object ValueObject
+	fields []string
+}
+
+func (o sensitiveObject) ObjectFieldCount() int {
+	return o.object.ObjectFieldCount()
+}
+
+func (o sensitiveObject) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	o.object.AcceptObjectFieldVisitor(sensitiveObjectFieldVisitor{visitor, o.fields})
+}
+
+type sensitiveObjectFieldVisitor struct {
+	visitor ObjectFieldVisitor
+	fields  []string
+}
+
+func (v sensitiveObjectFieldVisitor) VisitObjectField(key string, value Value) {
+	for _, field := range v.fields {
+		if field == key {
+			value = String(DefaultMask)
+
+			break
+		}
+	}
+
+	v.visitor.VisitObjectField(key, value)
+}
@@ -0,0 +1,215 @@
+package valf
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSensitiveObject mockObject
+
+func (o testSensitiveObject) ObjectFieldCount() int {
+	return mockObject(o).ObjectFieldCount()
+}
+
+func (o testSensitiveObject) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	mockObject(o).AcceptObjectFieldVisitor(visitor)
+}
+
+func (o testSensitiveObject) SensitiveFields() []string {
+	return []string{"pin"}
+}
+
+func redactedFields(t *testing.T, v Value) map[string]Value {
+	visitor := newMockObjectVisitor(t)
+	v.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+
+	return visitor.value
+}
+
+func TestRedactKeys(t *testing.T) {
+	r := NewRedactor(RedactKeys(MaskWith("***"), "password", "token"))
+	value := r.Redact(Object(mockObject{
+		"user":     String("john"),
+		"Password": String("secret"),
+		"nested":   Object(mockObject{"token": Int(42), "id": Int(1)}),
+		"list":     Array(mockArray{Object(mockObject{"token": String("t")})}),
+	}))
+
+	fields := redactedFields(t, value)
+	require.Equal(t, String("john"), fields["user"])
+	require.Equal(t, String("***"), fields["Password"])
+
+	nested := redactedFields(t, fields["nested"])
+	require.Equal(t, String("***"), nested["token"])
+	require.Equal(t, Int(1), nested["id"])
+
+	visitor := newMockArrayVisitor(t)
+	fields["list"].AcceptVisitor(visitor)
+	require.Equal(t, String("***"), redactedFields(t, visitor.value[0])["token"])
+}
+
+func TestRedactPaths(t *testing.T) {
+	r := NewRedactor(RedactPaths(MaskWith("***"), "user.password", "**.secret", "*.key"))
+	value := r.Redact(Object(mockObject{
+		"password": String("visible"),
+		"user":     Object(mockObject{"password": String("hidden"), "key": String("hidden")}),
+		"a":        Object(mockObject{"b": Object(mockObject{"secret": String("hidden"), "key": String("visible")})}),
+		"secret":   String("hidden"),
+	}))
+
+	fields := redactedFields(t, value)
+	require.Equal(t, String("visible"), fields["password"])
+	require.Equal(t, String("***"), fields["secret"])
+
+	user := redactedFields(t, fields["user"])
+	require.Equal(t, String("***"), user["password"])
+	require.Equal(t, String("***"), user["key"])
+
+	b := redactedFields(t, redactedFields(t, fields["a"])["b"])
+	require.Equal(t, String("***"), b["secret"])
+	require.Equal(t, String("visible"), b["key"])
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    []string
+		key     string
+		match   bool
+	}{
+		{"user.password", []string{"user"}, "password", true},
+		{"user.password", nil, "password", false},
+		{"*.key", []string{"user"}, "key", true},
+		{"*.key", []string{"a", "b"}, "key", false},
+		{"**.token", nil, "token", true},
+		{"**.token", []string{"a", "b"}, "token", true},
+		{"**.token", []string{"a"}, "other", false},
+		{"user.**", []string{"user"}, "x", true},
+		{"user.**", []string{"user", "a"}, "x", true},
+		{"user.**", nil, "user", true},
+		{"user.**", []string{"other"}, "x", false},
+		{"a.**.z", []string{"a"}, "z", true},
+		{"a.**.z", []string{"a", "b", "c"}, "z", true},
+		{"a.**.z", []string{"a", "b"}, "y", false},
+		{"**", nil, "x", true},
+		{"**", []string{"a", "b"}, "x", true},
+		{"**.**", []string{"a"}, "x", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			require.Equal(t, tt.match, matchPath(strings.Split(tt.pattern, "."), tt.path, tt.key))
+		})
+	}
+}
+
+func TestRedactPathsTrailingWildcard(t *testing.T) {
+	r := NewRedactor(RedactPaths(MaskWith("***"), "user.**"))
+	value := r.Redact(Object(mockObject{
+		"user": Object(mockObject{"password": String("hidden")}),
+		"name": String("visible"),
+	}))
+
+	fields := redactedFields(t, value)
+	require.Equal(t, String("***"), fields["user"])
+	require.Equal(t, String("visible"), fields["name"])
+}
+
+func TestRedactRaw(t *testing.T) {
+	r := NewRedactor(
+		RedactKeys(MaskWith("***"), "password"),
+		RedactPattern(MaskWith("#"), regexp.MustCompile(`\d{4}`)),
+	)
+	value := r.Redact(Object(mockObject{
+		"json":   RawJSON([]byte(`{"password":"secret","user":{"password":"x"},"pin":"1234"}`)),
+		"text":   RawText([]byte("pin 1234")),
+		"binary": Raw(EncodingMsgPack, []byte{0x81}),
+		"broken": RawJSON([]byte(`{"password":`)),
+	}))
+
+	fields := redactedFields(t, value)
+	json := redactedFields(t, fields["json"])
+	require.Equal(t, String("***"), json["password"])
+	require.Equal(t, String("#"), json["pin"])
+	require.Equal(t, String("***"), redactedFields(t, json["user"])["password"])
+	require.Equal(t, String("pin #"), fields["text"])
+	require.Equal(t, String("***"), fields["binary"])
+	require.Equal(t, String("***"), fields["broken"])
+
+	require.Equal(t, RawJSON([]byte(`{}`)), NewRedactor().Redact(RawJSON([]byte(`{}`))))
+}
+
+func TestRedactPattern(t *testing.T) {
+	r := NewRedactor(RedactPattern(MaskPartial(0, 4), regexp.MustCompile(`\d{16}`)))
+
+	require.Equal(t, String("card ************3456 used"), r.Redact(String("card 1234567890123456 used")))
+	require.Equal(t, String("no card"), r.Redact(String("no card")))
+	require.Equal(t, String("************3456"), r.Redact(Stringer(testStringer("1234567890123456"))))
+	require.Equal(t, ConstStrings([]string{"a", "************3456"}), r.Redact(Strings([]string{"a", "1234567890123456"})))
+}
+
+func TestRedactPatternErrors(t *testing.T) {
+	r := NewRedactor(RedactPattern(MaskWith("***"), regexp.MustCompile(`secret\d+`)))
+
+	err := fmt.Errorf("wrapped: %w", errors.New("bad token secret123"))
+	visitor := newMockErrorVisitor(t)
+	r.Snapshot(Error(err)).AcceptVisitor(visitor)
+	require.Equal(t, "wrapped: bad token ***", visitor.value.Error())
+	require.Nil(t, errors.Unwrap(visitor.value))
+	require.Equal(t, String("*fmt.wrapError"), errorDetailsFields(t, ErrorDetails(visitor.value))[ErrorFieldType])
+
+	plain := errors.New("no secrets")
+	require.Equal(t, Error(plain), r.Redact(Error(plain)))
+
+	require.Equal(t,
+		ConstStrings([]string{"<nil>", "bad token ***"}),
+		r.Snapshot(Errors([]error{nil, errors.New("bad token secret123")})),
+	)
+	require.Equal(t,
+		ConstStrings([]string{"***", "a"}),
+		r.Snapshot(Stringers([]fmt.Stringer{testStringer("secret1"), testStringer("a")})),
+	)
+	require.Equal(t, ConstErrors([]error{plain}), r.Redact(ConstErrors([]error{plain})))
+}
+
+func TestRedactTypes(t *testing.T) {
+	r := NewRedactor(RedactTypes(MaskHash([]byte("key")), TypeInt))
+	value := r.Redact(Object(mockObject{"n": Int(42), "s": String("42")}))
+
+	fields := redactedFields(t, value)
+	require.Equal(t, String("hmac-sha256:f2991b7ce981d0b5"), fields["n"])
+	require.Equal(t, String("42"), fields["s"])
+}
+
+func TestRedactSensitiveObject(t *testing.T) {
+	r := NewRedactor()
+	value := r.Redact(Object(testSensitiveObject{"pin": Int(1234), "name": String("card")}))
+
+	fields := redactedFields(t, value)
+	require.Equal(t, String(DefaultMask), fields["pin"])
+	require.Equal(t, String("card"), fields["name"])
+}
+
+func TestRedactSnapshot(t *testing.T) {
+	r := NewRedactor(RedactKeys(MaskWith("***"), "password"))
+	v := mockObject{"password": String("secret"), "user": String("john")}
+	value := r.Snapshot(ConstObject(v))
+	require.Equal(t, true, value.Const())
+	v["user"] = String("other")
+
+	fields := redactedFields(t, value)
+	require.Equal(t, String("***"), fields["password"])
+	require.Equal(t, String("john"), fields["user"])
+}
+
+func TestMaskPartial(t *testing.T) {
+	require.Equal(t, String("ab**"), MaskPartial(2, 0)(String("abcd")))
+	require.Equal(t, String("***"), MaskPartial(2, 2)(String("abc")))
+	require.Equal(t, String("1*3"), MaskPartial(1, 1)(Int(123)))
+}
@@ -0,0 +1,201 @@
+package valf
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// Sizes of structures referenced by values.
+const (
+	valueSize       = int(unsafe.Sizeof(Value{}))
+	stringSize      = int(unsafe.Sizeof(""))
+	interfaceSize   = int(unsafe.Sizeof(interface{}(nil)))
+	timeSize        = int(unsafe.Sizeof(time.Time{}))
+	fieldSize       = int(unsafe.Sizeof(objectField{}))
+	annotationSize  = int(unsafe.Sizeof(Annotation{}))
+	wordSize        = int(unsafe.Sizeof(big.Word(0)))
+	maxReflectDepth = 4
+	maxSizeDepth    = 100
+)
+
+// SizeOf returns the approximate number of bytes of memory retained by v,
+// not counting the Value itself. It includes strings, typed slices and
+// nested arrays and objects. The size of data referenced by Any, Stringer
+// and error values is estimated using reflection. Lazy values are not
+// evaluated, so their size is zero. Arrays and objects nested deeper than
+// 100 levels are not counted, so self-referential values are safe.
+func SizeOf(v Value) int {
+	return sizeOf(v, 0)
+}
+
+func sizeOf(v Value, depth int) int {
+	n := len(v.vString)
+
+	switch t := v.bits.Type(); t {
+	case TypeStrings:
+		for _, s := range v.vAny.([]string) {
+			n += stringSize + len(s)
+		}
+	case TypeTimes:
+		n += timeSize * len(v.vAny.([]time.Time))
+	case TypeErrors:
+		n += interfaceSize * len(v.vAny.([]error))
+	case TypeStringers:
+		n += interfaceSize * len(v.vAny.([]fmt.Stringer))
+	case TypeArray:
+		if v.vAny != nil && depth != maxSizeDepth {
+			s := sizeVisitor{depth: depth + 1}
+			v.vAny.(ValueArray).AcceptArrayItemVisitor(&s)
+			n += s.size
+		}
+	case TypeObject:
+		if v.vAny != nil && depth != maxSizeDepth {
+			s := sizeVisitor{depth: depth + 1}
+			v.vAny.(ValueObject).AcceptObjectFieldVisitor(&s)
+			n += s.size
+		}
+	case TypeAnnotated:
+		a := v.vAny.(*annotated)
+		n += valueSize + sizeOf(a.value, depth)
+		for _, annotation := range a.annotations {
+			n += annotationSize + len(annotation.Key) + len(annotation.Value)
+		}
+	case TypeBigInt, TypeDecimal:
+		if v.vAny != nil {
+			n += wordSize * len(v.vAny.(*big.Int).Bits())
+		}
+	case TypeBigFloat:
+		if v.vAny != nil {
+			n += int(v.vAny.(*big.Float).MinPrec()) / 8
+		}
+	case TypeBigRat:
+		if v.vAny != nil {
+			r := v.vAny.(*big.Rat)
+			n += wordSize * (len(r.Num().Bits()) + len(r.Denom().Bits()))
+		}
+	case TypeError:
+		if s, ok := v.vAny.(*errorSnapshot); ok {
+			n += errorSnapshotSize(s)
+		} else if v.vAny != nil {
+			n += reflectSize(v.vAny)
+		}
+	case TypeAny, TypeStringer, TypeFormatter:
+		if v.vAny != nil {
+			n += reflectSize(v.vAny)
+		}
+	case TypeRaw:
+		n += len(v.vBytes)
+		if v.vAny != nil {
+			n += int(unsafe.Sizeof(rawJSON{}))
+		}
+	case TypeLazy, TypeTime:
+	default:
+		n += len(v.vBytes) * itemSize(t)
+	}
+
+	return n
+}
+
+type sizeVisitor struct {
+	size  int
+	depth int
+}
+
+func (s *sizeVisitor) VisitArrayItem(_ int, value Value) {
+	s.size += valueSize + sizeOf(value, s.depth)
+}
+
+func (s *sizeVisitor) VisitObjectField(key string, value Value) {
+	s.size += fieldSize + len(key) + sizeOf(value, s.depth)
+}
+
+func errorSnapshotSize(s *errorSnapshot) int {
+	n := int(unsafe.Sizeof(*s)) + len(s.message) + len(s.typeName)
+	for _, cause := range s.causes {
+		n += interfaceSize
+		if cs, ok := cause.(*errorSnapshot); ok {
+			n += errorSnapshotSize(cs)
+		} else {
+			n += reflectSize(cause)
+		}
+	}
+	if s.fields != nil {
+		n += SizeOf(Object(s.fields))
+	}
+	if s.origin != nil {
+		n += reflectSize(s.origin)
+	}
+
+	return n
+}
+
+// itemSize returns the size of items of typed slices stored in vBytes
+// or zero for other types.
+func itemSize(t Type) int {
+	switch t {
+	case TypeBytes, TypeMAC, TypeRaw, TypeBools, TypeInts8, TypeUints8:
+		return 1
+	case TypeInts16, TypeUints16:
+		return 2
+	case TypeInts32, TypeUints32, TypeFloats32:
+		return 4
+	case TypeInts64, TypeUints64, TypeFloats64, TypeDurations, TypeComplexes64:
+		return 8
+	case TypeComplexes128:
+		return 16
+	case TypeInts, TypeUints, TypeUintptrs:
+		return int(unsafe.Sizeof(uintptr(0)))
+	}
+
+	return 0
+}
+
+// reflectSize estimates the size of memory referenced by an interface
+// including the value stored in it.
+func reflectSize(v interface{}) int {
+	rv := reflect.ValueOf(v)
+
+	return int(rv.Type().Size()) + reflectIndirectSize(rv, 0)
+}
+
+// reflectIndirectSize estimates the size of memory referenced by rv
+// not counting rv itself. Deep structures are estimated partially.
+func reflectIndirectSize(rv reflect.Value, depth int) int {
+	if depth == maxReflectDepth {
+		return 0
+	}
+
+	n := 0
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !rv.IsNil() {
+			n += int(rv.Elem().Type().Size()) + reflectIndirectSize(rv.Elem(), depth+1)
+		}
+	case reflect.String:
+		n += rv.Len()
+	case reflect.Slice:
+		n += rv.Cap() * int(rv.Type().Elem().Size())
+		for i := 0; i != rv.Len(); i++ {
+			n += reflectIndirectSize(rv.Index(i), depth+1)
+		}
+	case reflect.Array:
+		for i := 0; i != rv.Len(); i++ {
+			n += reflectIndirectSize(rv.Index(i), depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i != rv.NumField(); i++ {
+			n += reflectIndirectSize(rv.Field(i), depth+1)
+		}
+	case reflect.Map:
+		entrySize := int(rv.Type().Key().Size() + rv.Type().Elem().Size())
+		iter := rv.MapRange()
+		for iter.Next() {
+			n += entrySize + reflectIndirectSize(iter.Key(), depth+1) + reflectIndirectSize(iter.Value(), depth+1)
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,68 @@
+package valf
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeOf(t *testing.T) {
+	require.Equal(t, 0, SizeOf(Int(42)))
+	require.Equal(t, 0, SizeOf(Time(time.Now())))
+	require.Equal(t, 5, SizeOf(String("hello")))
+	require.Equal(t, 3, SizeOf(Bytes([]byte("abc"))))
+	require.Equal(t, 24, SizeOf(Ints64([]int64{1, 2, 3})))
+	require.Equal(t, 8, SizeOf(Floats32([]float32{1, 2})))
+	require.Equal(t, 2*stringSize+3, SizeOf(Strings([]string{"a", "bc"})))
+	require.Equal(t, valueSize*2+2, SizeOf(Array(mockArray{Int(1), String("ab")})))
+	require.Equal(t, fieldSize+1+4, SizeOf(Object(mockObject{"k": Ints32([]int32{1})})))
+	require.Equal(t, 0, SizeOf(Lazy(func() Value { return String("text") })))
+	require.Equal(t, 0, SizeOf(Complex128(1i)))
+	require.Equal(t, 0, SizeOf(UUID([16]byte{1})))
+	require.Equal(t, 0, SizeOf(IP(netip.MustParseAddr("::1"))))
+	require.Equal(t, 0, SizeOf(IP(netip.MustParseAddr("127.0.0.1"))))
+
+	require.True(t, SizeOf(Any(&testStruct{1})) >= 8)
+	require.True(t, SizeOf(Any([]string{"long string"})) > 11)
+	require.True(t, SizeOf(Error(errors.New("message"))) > 7)
+}
+
+type testSelfObject struct{}
+
+func (o testSelfObject) ObjectFieldCount() int {
+	return 1
+}
+
+func (o testSelfObject) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
+	visitor.VisitObjectField("self", Object(o))
+}
+
+func TestSizeOfSelfReferential(t *testing.T) {
+	require.Equal(t, maxSizeDepth*(fieldSize+len("self")), SizeOf(Object(testSelfObject{})))
+	require.Equal(t, 0, SizeOf(Any(testRecursiveValuer{})))
+}
+
+func TestSnapshotSize(t *testing.T) {
+	v := Int(42)
+	require.Equal(t, 0, SnapshotSize(&v))
+
+	v = ConstInts([]int{1, 2})
+	require.Equal(t, 0, SnapshotSize(&v))
+
+	v = Ints64([]int64{1, 2})
+	require.Equal(t, 16, SnapshotSize(&v))
+	require.Equal(t, true, v.Const())
+
+	v = Array(mockArray{Bytes([]byte("abc")), String("const")})
+	require.Equal(t, int(unsafe.Sizeof(arraySnapshot{}))+valueSize*2+3, SnapshotSize(&v))
+
+	v = Strings([]string{"a", "b"})
+	require.Equal(t, 2*stringSize, SnapshotSize(&v))
+
+	v = Errors([]error{errors.New("failure")})
+	require.Equal(t, stringSize+7, SnapshotSize(&v))
+}
@@ -3,6 +3,9 @@ package valf
 import (
 	"errors"
 	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
 	"time"
 	"unsafe"
 )
@@ -15,195 +18,279 @@ type Snapshotter interface {
 // Snapshot changes the v so that it can be safely stored for a long with guarantee that it won't be modified.
 // The data of the value are copied if it should be copied to achieve that guarantee.
 func Snapshot(v *Value) {
+	snapshotContext{}.snapshot(v)
+}
+
+// SnapshotSize changes the v in the same way as Snapshot does and returns
+// the approximate number of bytes allocated for the copied data, see SizeOf.
+func SnapshotSize(v *Value) int {
+	var allocated int
+	snapshotContext{allocated: &allocated}.snapshot(v)
+
+	return allocated
+}
+
+// SnapshotOptions configures SnapshotWith.
+type SnapshotOptions struct {
+	// Arena is used to allocate copies of data if it is not nil, see SnapshotAll.
+	Arena *Arena
+
+	// Interner is used to deduplicate object keys and strings if it is not nil.
+	Interner *Interner
+
+	// ParallelThreshold is the minimum number of items of an array or fields
+	// of an object to snapshot them in parallel. Zero disables parallel
+	// snapshotting. Only arrays and objects implementing StableItems,
+	// e.g. ones built by Values, AnySlice and Fields, are snapshotted
+	// in parallel. Values snapshotted in parallel, e.g. lazy
+	// values and nested arrays, must be safe for concurrent use. The Arena is
+	// used only by the calling goroutine.
+	ParallelThreshold int
+
+	// MaxWorkers is the maximum number of goroutines used for parallel
+	// snapshotting in addition to the calling one.
+	// Zero means runtime.GOMAXPROCS(0) - 1.
+	MaxWorkers int
+}
+
+// SnapshotWith changes the v in the same way as Snapshot does using the
+// given options and returns the approximate number of bytes allocated for
+// the copied data, see SnapshotSize.
+func SnapshotWith(v *Value, options SnapshotOptions) int {
+	var allocated int
+	c := snapshotContext{arena: options.Arena, interner: options.Interner, allocated: &allocated}
+	if options.ParallelThreshold > 0 {
+		c.parallel = newParallelism(options.ParallelThreshold, options.MaxWorkers)
+	}
+	c.snapshot(v)
+
+	return allocated
+}
+
+// snapshotContext holds the state shared by all values snapshotted in a single call.
+type snapshotContext struct {
+	arena     *Arena
+	interner  *Interner
+	allocated *int
+	parallel  *parallelism
+}
+
+func (c snapshotContext) intern(s string) string {
+	if c.interner == nil {
+		return s
+	}
+
+	return c.interner.Intern(s)
+}
+
+func (c snapshotContext) snapshot(v *Value) {
 	if !v.bits.Const() {
-		switch v.bits.Type() {
+		t := v.bits.Type()
+		switch t {
 		case TypeNone:
 		case TypeAny:
 			snapshotAny(v)
-		case TypeBytes:
-			snapshotBytes(v)
+		case TypeError:
+			snapshotError(v)
+		case TypeBytes, TypeMAC:
+			snapshotSlice[byte](c, v)
+		case TypeRaw:
+			c.snapshotRaw(v)
 		case TypeBools:
-			snapshotBools(v)
+			snapshotSlice[bool](c, v)
 		case TypeInts:
-			snapshotInts(v)
+			snapshotSlice[int](c, v)
 		case TypeInts8:
-			snapshotInts8(v)
+			snapshotSlice[int8](c, v)
 		case TypeInts16:
-			snapshotInts16(v)
+			snapshotSlice[int16](c, v)
 		case TypeInts32:
-			snapshotInts32(v)
+			snapshotSlice[int32](c, v)
 		case TypeInts64:
-			snapshotInts64(v)
+			snapshotSlice[int64](c, v)
 		case TypeUints:
-			snapshotUints(v)
+			snapshotSlice[uint](c, v)
 		case TypeUints8:
-			snapshotUints8(v)
+			snapshotSlice[uint8](c, v)
 		case TypeUints16:
-			snapshotUints16(v)
+			snapshotSlice[uint16](c, v)
 		case TypeUints32:
-			snapshotUints32(v)
+			snapshotSlice[uint32](c, v)
 		case TypeUints64:
-			snapshotUints64(v)
+			snapshotSlice[uint64](c, v)
 		case TypeFloats32:
-			snapshotFloats32(v)
+			snapshotSlice[float32](c, v)
 		case TypeFloats64:
-			snapshotFloats64(v)
+			snapshotSlice[float64](c, v)
 		case TypeDurations:
-			snapshotDurations(v)
+			snapshotSlice[time.Duration](c, v)
+		case TypeComplexes64:
+			snapshotSlice[complex64](c, v)
+		case TypeComplexes128:
+			snapshotSlice[complex128](c, v)
+		case TypeUintptrs:
+			snapshotSlice[uintptr](c, v)
 		case TypeStrings:
-			snapshotStrings(v)
+			c.snapshotStrings(v)
+		case TypeTimes:
+			snapshotTimes(v)
+		case TypeErrors:
+			c.snapshotErrors(v)
+		case TypeStringers:
+			c.snapshotStringers(v)
 		case TypeArray:
-			snapshotArray(v)
+			c.snapshotArray(v)
 		case TypeObject:
-			snapshotObject(v)
+			c.snapshotObject(v)
 		case TypeStringer:
 			snapshotStringer(v)
 		case TypeFormatter:
 			snapshotFormatter(v)
+		case TypeLazy:
+			c.snapshotLazy(v)
+		case TypeAnnotated:
+			c.snapshotAnnotated(v)
+		case TypeBigInt, TypeDecimal:
+			snapshotBigInt(v)
+		case TypeBigFloat:
+			snapshotBigFloat(v)
+		case TypeBigRat:
+			snapshotBigRat(v)
 
 		default:
 			panic(fmt.Errorf("snapf: internal error: unhandled value type: %v", v.bits.Type()))
 		}
+
+		if c.allocated != nil {
+			*c.allocated += snapshotAllocated(t, *v)
+		}
 	}
-}
 
-func snapshotBytes(v *Value) {
-	cc := make([]byte, len(v.vBytes))
-	copy(cc, v.vBytes)
-	v.vBytes = cc
-	v.bits |= bitsConst
+	if c.interner != nil && v.bits.Type() == TypeString {
+		v.vString = c.interner.Intern(v.vString)
+	}
 }
 
-func snapshotBools(v *Value) {
-	s := *(*[]bool)(unsafe.Pointer(&v.vBytes))
-	cc := make([]bool, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
-	v.bits |= bitsConst
-}
+// snapshotAllocated returns the number of bytes allocated to snapshot
+// a value of type t resulting in v not counting nested values.
+func snapshotAllocated(t Type, v Value) int {
+	switch t {
+	case TypeNone, TypeLazy:
+		return 0
+	case TypeArray:
+		return int(unsafe.Sizeof(arraySnapshot{})) + valueSize*len(v.vAny.(arraySnapshot).items)
+	case TypeObject:
+		return int(unsafe.Sizeof(objectSnapshot{})) + fieldSize*cap(v.vAny.(objectSnapshot).fields)
+	case TypeAnnotated:
+		return int(unsafe.Sizeof(annotated{})) + annotationSize*len(v.Annotations())
+	case TypeStrings:
+		return stringSize * len(v.vAny.([]string))
+	}
 
-func snapshotInts(v *Value) {
-	s := *(*[]int)(unsafe.Pointer(&v.vBytes))
-	cc := make([]int, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
-	v.bits |= bitsConst
+	return SizeOf(v)
 }
 
-func snapshotInts8(v *Value) {
-	s := *(*[]int8)(unsafe.Pointer(&v.vBytes))
-	cc := make([]int8, len(s))
-	copy(cc, s)
+// snapshotSlice copies a typed slice stored in vBytes with items of type T.
+func snapshotSlice[T any](c snapshotContext, v *Value) {
+	s := *(*[]T)(unsafe.Pointer(&v.vBytes))
+	cc := arenaCopy(c.arena, s)
 	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
 	v.bits |= bitsConst
 }
 
-func snapshotInts16(v *Value) {
-	s := *(*[]int16)(unsafe.Pointer(&v.vBytes))
-	cc := make([]int16, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
-	v.bits |= bitsConst
+func (c snapshotContext) snapshotRaw(v *Value) {
+	snapshotSlice[byte](c, v)
+	if Encoding(v.vInt) == EncodingJSON && len(v.vBytes) != 0 {
+		v.vAny = &rawJSON{}
+	}
 }
 
-func snapshotInts32(v *Value) {
-	s := *(*[]int32)(unsafe.Pointer(&v.vBytes))
-	cc := make([]int32, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
-	v.bits |= bitsConst
+func snapshotStringer(v *Value) {
+	v.vString = v.vAny.(fmt.Stringer).String()
+	v.vAny = nil
+	v.bits = bits(TypeString) | bitsConst
 }
 
-func snapshotInts64(v *Value) {
-	s := *(*[]int64)(unsafe.Pointer(&v.vBytes))
-	cc := make([]int64, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
-	v.bits |= bitsConst
+func snapshotFormatter(v *Value) {
+	v.vString = fmt.Sprintf(v.vString, v.vAny)
+	v.vAny = nil
+	v.bits = bits(TypeString) | bitsConst
 }
 
-func snapshotUints(v *Value) {
-	s := *(*[]uint)(unsafe.Pointer(&v.vBytes))
-	cc := make([]uint, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
+func (c snapshotContext) snapshotStrings(v *Value) {
+	s := v.vAny.([]string)
+	cc := c.arena.allocStrings(len(s))
+	for i, item := range s {
+		cc[i] = c.intern(item)
+	}
+	v.vAny = cc
 	v.bits |= bitsConst
 }
 
-func snapshotUints8(v *Value) {
-	s := *(*[]uint8)(unsafe.Pointer(&v.vBytes))
-	cc := make([]uint8, len(s))
+func snapshotTimes(v *Value) {
+	s := v.vAny.([]time.Time)
+	cc := make([]time.Time, len(s))
 	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
+	v.vAny = cc
 	v.bits |= bitsConst
 }
 
-func snapshotUints16(v *Value) {
-	s := *(*[]uint16)(unsafe.Pointer(&v.vBytes))
-	cc := make([]uint16, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
-	v.bits |= bitsConst
+func (c snapshotContext) snapshotErrors(v *Value) {
+	s := v.vAny.([]error)
+	cc := c.arena.allocStrings(len(s))
+	for i, err := range s {
+		if err != nil {
+			cc[i] = c.intern(err.Error())
+		} else {
+			cc[i] = nilText
+		}
+	}
+	*v = ConstStrings(cc)
 }
 
-func snapshotUints32(v *Value) {
-	s := *(*[]uint32)(unsafe.Pointer(&v.vBytes))
-	cc := make([]uint32, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
-	v.bits |= bitsConst
+func (c snapshotContext) snapshotStringers(v *Value) {
+	s := v.vAny.([]fmt.Stringer)
+	cc := c.arena.allocStrings(len(s))
+	for i, item := range s {
+		if item != nil {
+			cc[i] = c.intern(item.String())
+		} else {
+			cc[i] = nilText
+		}
+	}
+	*v = ConstStrings(cc)
 }
 
-func snapshotUints64(v *Value) {
-	s := *(*[]uint64)(unsafe.Pointer(&v.vBytes))
-	cc := make([]uint64, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
-	v.bits |= bitsConst
-}
+// nilText is the text used for nil items of snapshotted slices of errors and Stringers.
+const nilText = "<nil>"
 
-func snapshotFloats32(v *Value) {
-	s := *(*[]float32)(unsafe.Pointer(&v.vBytes))
-	cc := make([]float32, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
+func snapshotBigInt(v *Value) {
+	v.vAny = new(big.Int).Set(v.vAny.(*big.Int))
 	v.bits |= bitsConst
 }
 
-func snapshotFloats64(v *Value) {
-	s := *(*[]float64)(unsafe.Pointer(&v.vBytes))
-	cc := make([]float64, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
+func snapshotBigFloat(v *Value) {
+	v.vAny = new(big.Float).Copy(v.vAny.(*big.Float))
 	v.bits |= bitsConst
 }
 
-func snapshotDurations(v *Value) {
-	s := *(*[]time.Duration)(unsafe.Pointer(&v.vBytes))
-	cc := make([]time.Duration, len(s))
-	copy(cc, s)
-	v.vBytes = *(*[]byte)(unsafe.Pointer(&cc))
+func snapshotBigRat(v *Value) {
+	v.vAny = new(big.Rat).Set(v.vAny.(*big.Rat))
 	v.bits |= bitsConst
 }
 
-func snapshotStringer(v *Value) {
-	v.vString = v.vAny.(fmt.Stringer).String()
-	v.vAny = nil
-	v.bits = bits(TypeString) | bitsConst
-}
-
-func snapshotFormatter(v *Value) {
-	v.vString = fmt.Sprintf(v.vString, v.vAny)
-	v.vAny = nil
-	v.bits = bits(TypeString) | bitsConst
+func (c snapshotContext) snapshotLazy(v *Value) {
+	*v = v.Resolve()
+	c.snapshot(v)
 }
 
-func snapshotStrings(v *Value) {
-	s := v.vAny.([]string)
-	cc := make([]string, len(s))
-	copy(cc, s)
-	v.vAny = cc
-	v.bits |= bitsConst
+func (c snapshotContext) snapshotAnnotated(v *Value) {
+	a := v.vAny.(*annotated)
+	value := a.value
+	c.snapshot(&value)
+	annotations := make([]Annotation, len(a.annotations))
+	copy(annotations, a.annotations)
+	*v = Annotated(value, annotations...)
 }
 
 func snapshotAny(v *Value) {
@@ -215,20 +302,31 @@ func snapshotAny(v *Value) {
 	*v = ConstAny(snapshotter.TakeSnapshot())
 }
 
-func snapshotArray(v *Value) {
+func (c snapshotContext) snapshotArray(v *Value) {
 	a := v.vAny.(ValueArray)
-	s := arraySnapshotter{arraySnapshot{make([]Value, a.ArrayItemCount())}}
+	n := a.ArrayItemCount()
+	s := arraySnapshotter{c, arraySnapshot{c.arena.allocValues(n)}, c.parallel.accepts(a, n)}
 	a.AcceptArrayItemVisitor(&s)
+	if s.deferred {
+		items := s.snapshot.items
+		c.parallel.run(c, len(items), func(c snapshotContext, i int) {
+			c.snapshot(&items[i])
+		})
+	}
 	v.vAny = s.snapshot
 	v.bits |= bitsConst
 }
 
 type arraySnapshotter struct {
+	context  snapshotContext
 	snapshot arraySnapshot
+	deferred bool
 }
 
 func (s *arraySnapshotter) VisitArrayItem(index int, value Value) {
-	Snapshot(&value)
+	if !s.deferred {
+		s.context.snapshot(&value)
+	}
 	s.snapshot.items[index] = value
 }
 
@@ -251,21 +349,32 @@ type objectField struct {
 	Value Value
 }
 
-func snapshotObject(v *Value) {
+func (c snapshotContext) snapshotObject(v *Value) {
 	o := v.vAny.(ValueObject)
-	s := objectSnapshotter{objectSnapshot{make([]objectField, 0, o.ObjectFieldCount())}}
+	n := o.ObjectFieldCount()
+	s := objectSnapshotter{c, objectSnapshot{c.arena.allocFields(n)}, c.parallel.accepts(o, n)}
 	o.AcceptObjectFieldVisitor(&s)
+	if s.deferred {
+		fields := s.snapshot.fields
+		c.parallel.run(c, len(fields), func(c snapshotContext, i int) {
+			c.snapshot(&fields[i].Value)
+		})
+	}
 	v.vAny = s.snapshot
 	v.bits |= bitsConst
 }
 
 type objectSnapshotter struct {
+	context  snapshotContext
 	snapshot objectSnapshot
+	deferred bool
 }
 
 func (s *objectSnapshotter) VisitObjectField(name string, value Value) {
-	Snapshot(&value)
-	s.snapshot.fields = append(s.snapshot.fields, objectField{name, value})
+	if !s.deferred {
+		s.context.snapshot(&value)
+	}
+	s.snapshot.fields = append(s.snapshot.fields, objectField{s.context.intern(name), value})
 }
 
 type objectSnapshot struct {
@@ -281,3 +390,132 @@ func (s objectSnapshot) AcceptObjectFieldVisitor(visitor ObjectFieldVisitor) {
 		visitor.VisitObjectField(field.Name, field.Value)
 	}
 }
+
+func snapshotError(v *Value) {
+	v.vAny = takeErrorSnapshot(v.vAny.(error))
+	v.bits |= bitsConst
+}
+
+// takeErrorSnapshot returns an immutable copy of err. Errors implementing
+// Snapshotter provide their own copy if TakeSnapshot returns an error,
+// otherwise the message, type name, causes and fields are captured.
+func takeErrorSnapshot(err error) error {
+	if s, ok := err.(Snapshotter); ok {
+		if snapshot, ok := s.TakeSnapshot().(error); ok && snapshot != nil {
+			return snapshot
+		}
+	}
+
+	return newErrorSnapshot(err)
+}
+
+// errorSnapshot is an immutable copy of an error which keeps its message,
+// type name, causes and fields.
+//
+// The original error is kept to be matched by errors.Is if it is comparable.
+// It is a trade-off: errors.Is works for sentinel and other errors compared
+// by identity, but the data referenced by the original error is retained
+// by the snapshot as well. errors.As finds the original error only if its
+// type is plain, see isPlainType, since copies of other errors share their
+// mutable data with the original.
+type errorSnapshot struct {
+	message  string
+	typeName string
+	causes   []error
+	fields   ValueObject
+	origin   error
+}
+
+func newErrorSnapshot(err error) *errorSnapshot {
+	if s, ok := err.(*errorSnapshot); ok {
+		return s
+	}
+
+	s := &errorSnapshot{message: err.Error(), typeName: errorTypeName(err)}
+	if reflect.TypeOf(err).Comparable() {
+		s.origin = err
+	}
+
+	causes := unwrapError(err)
+	if len(causes) != 0 {
+		s.causes = make([]error, len(causes))
+		for i, cause := range causes {
+			s.causes[i] = takeErrorSnapshot(cause)
+		}
+	}
+
+	if o, ok := err.(ValueObject); ok {
+		s.fields = Object(o).Snapshot().vAny.(ValueObject)
+	}
+
+	return s
+}
+
+func (s *errorSnapshot) Error() string {
+	return s.message
+}
+
+func (s *errorSnapshot) Unwrap() []error {
+	return s.causes
+}
+
+func (s *errorSnapshot) Is(target error) bool {
+	return s.origin != nil && s.origin == target
+}
+
+func (s *errorSnapshot) As(target interface{}) bool {
+	if s.origin == nil {
+		return false
+	}
+
+	origin := reflect.ValueOf(s.origin)
+	if !isPlainType(origin.Type()) {
+		return false
+	}
+
+	dst := reflect.ValueOf(target).Elem()
+	if !origin.Type().AssignableTo(dst.Type()) {
+		return false
+	}
+	dst.Set(origin)
+
+	return true
+}
+
+// plainTypes caches results of isPlainType by type.
+var plainTypes sync.Map // map[reflect.Type]bool
+
+// isPlainType reports whether values of type t reference no mutable data,
+// i.e. t has no pointers, slices, maps, interfaces, channels or functions.
+// Strings are allowed since they are immutable.
+func isPlainType(t reflect.Type) bool {
+	if ok, found := plainTypes.Load(t); found {
+		return ok.(bool)
+	}
+
+	ok, _ := plainTypes.LoadOrStore(t, checkPlainType(t))
+
+	return ok.(bool)
+}
+
+func checkPlainType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return checkPlainType(t.Elem())
+	case reflect.Struct:
+		for i := 0; i != t.NumField(); i++ {
+			if !checkPlainType(t.Field(i).Type) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
@@ -3,6 +3,9 @@ package valf
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -348,7 +351,46 @@ var snapshotTests = []snapshotTestSpec{
 	{
 		Name: "Error",
 		Generate: func() (Value, Value, func()) {
-			return Error(context.Canceled), Error(context.Canceled), func() {}
+			golden := &errorSnapshot{message: context.Canceled.Error(), typeName: "*errors.errorString", origin: context.Canceled}
+			return Error(context.Canceled), ConstError(golden), func() {}
+		},
+	},
+	{
+		Name: "MutableError",
+		Generate: func() (Value, Value, func()) {
+			err := &testMutableError{"original"}
+			golden := &errorSnapshot{message: "original", typeName: "*valf.testMutableError", origin: err}
+			return Error(err), ConstError(golden), func() {
+				err.message = "modified"
+			}
+		},
+	},
+	{
+		Name: "WrappedError",
+		Generate: func() (Value, Value, func()) {
+			err := fmt.Errorf("wrapped: %w", context.Canceled)
+			golden := &errorSnapshot{
+				message:  "wrapped: context canceled",
+				typeName: "*fmt.wrapError",
+				causes:   []error{&errorSnapshot{message: "context canceled", typeName: "*errors.errorString", origin: context.Canceled}},
+				origin:   err,
+			}
+			return Error(err), ConstError(golden), func() {}
+		},
+	},
+	{
+		Name: "ConstError",
+		Generate: func() (Value, Value, func()) {
+			err := &testMutableError{"original"}
+			return ConstError(err), ConstError(&testMutableError{"modified"}), func() {
+				err.message = "modified"
+			}
+		},
+	},
+	{
+		Name: "NilError",
+		Generate: func() (Value, Value, func()) {
+			return Error(nil), ConstError(nil), func() {}
 		},
 	},
 	{
@@ -498,6 +540,156 @@ var snapshotTests = []snapshotTestSpec{
 			return Object(nil), ConstObject(nil), func() {}
 		},
 	},
+	{
+		Name: "Lazy",
+		Generate: func() (Value, Value, func()) {
+			v := []int{1, 2}
+			return Lazy(func() Value { return Ints(v) }), ConstInts([]int{1, 2}), func() {
+				v[0] = 42
+			}
+		},
+	},
+	{
+		Name: "LazyPanic",
+		Generate: func() (Value, Value, func()) {
+			return Lazy(func() Value { panic("failure") }), ConstError(fmt.Errorf("valf: lazy value panicked: failure")), func() {}
+		},
+	},
+	{
+		Name: "BigInt",
+		Generate: func() (Value, Value, func()) {
+			v := big.NewInt(42)
+			return BigInt(v), ConstBigInt(big.NewInt(42)), func() {
+				v.SetInt64(21)
+			}
+		},
+	},
+	{
+		Name: "BigFloat",
+		Generate: func() (Value, Value, func()) {
+			v := big.NewFloat(4.2)
+			return BigFloat(v), ConstBigFloat(big.NewFloat(4.2)), func() {
+				v.SetFloat64(2.1)
+			}
+		},
+	},
+	{
+		Name: "BigRat",
+		Generate: func() (Value, Value, func()) {
+			v := big.NewRat(4, 2)
+			return BigRat(v), ConstBigRat(big.NewRat(4, 2)), func() {
+				v.SetInt64(21)
+			}
+		},
+	},
+	{
+		Name: "Decimal",
+		Generate: func() (Value, Value, func()) {
+			v := big.NewInt(42)
+			return Decimal(v, -1), ConstDecimal(big.NewInt(42), -1), func() {
+				v.SetInt64(21)
+			}
+		},
+	},
+	{
+		Name: "ConstBigInt",
+		Generate: func() (Value, Value, func()) {
+			v := big.NewInt(42)
+			return ConstBigInt(v), ConstBigInt(big.NewInt(21)), func() {
+				v.SetInt64(21)
+			}
+		},
+	},
+	{
+		Name: "Complex128",
+		Generate: func() (Value, Value, func()) {
+			return Complex128(complex(1, 2)), Complex128(complex(1, 2)), func() {}
+		},
+	},
+	{
+		Name: "Complexes64",
+		Generate: func() (Value, Value, func()) {
+			v := []complex64{1, 2}
+			return Complexes64(v), ConstComplexes64([]complex64{1, 2}), func() {
+				v[0] = 42
+			}
+		},
+	},
+	{
+		Name: "Complexes128",
+		Generate: func() (Value, Value, func()) {
+			v := []complex128{1, 2}
+			return Complexes128(v), ConstComplexes128([]complex128{1, 2}), func() {
+				v[0] = 42
+			}
+		},
+	},
+	{
+		Name: "Uintptrs",
+		Generate: func() (Value, Value, func()) {
+			v := []uintptr{1, 2}
+			return Uintptrs(v), ConstUintptrs([]uintptr{1, 2}), func() {
+				v[0] = 42
+			}
+		},
+	},
+	{
+		Name: "IPPort",
+		Generate: func() (Value, Value, func()) {
+			v := netip.MustParseAddrPort("[fe80::1%eth0]:80")
+			return IPPort(v), IPPort(v), func() {}
+		},
+	},
+	{
+		Name: "MAC",
+		Generate: func() (Value, Value, func()) {
+			v := net.HardwareAddr{1, 2, 3, 4, 5, 6}
+			return MAC(v), ConstMAC(net.HardwareAddr{1, 2, 3, 4, 5, 6}), func() {
+				v[0] = 42
+			}
+		},
+	},
+	{
+		Name: "Raw",
+		Generate: func() (Value, Value, func()) {
+			v := []byte(`{"a":1}`)
+			golden := ConstRawJSON([]byte(`{"a":1}`))
+			golden.vAny = &rawJSON{}
+			return RawJSON(v), golden, func() {
+				v[0] = '['
+			}
+		},
+	},
+	{
+		Name: "Times",
+		Generate: func() (Value, Value, func()) {
+			v := []time.Time{time.Unix(1, 0)}
+			return Times(v), ConstTimes([]time.Time{time.Unix(1, 0)}), func() {
+				v[0] = time.Unix(2, 0)
+			}
+		},
+	},
+	{
+		Name: "Errors",
+		Generate: func() (Value, Value, func()) {
+			err := &testMutableError{"failure"}
+			v := []error{err, nil}
+			return Errors(v), ConstStrings([]string{"failure", "<nil>"}), func() {
+				err.message = "changed"
+				v[1] = err
+			}
+		},
+	},
+	{
+		Name: "Stringers",
+		Generate: func() (Value, Value, func()) {
+			s := testMutableStringer("text")
+			v := []fmt.Stringer{&s}
+			return Stringers(v), ConstStrings([]string{"text"}), func() {
+				s = "changed"
+			}
+		},
+	},
 	{
 		Name:       "CorruptedValue",
 		ShoudPanic: true,
@@ -513,6 +705,14 @@ func (s *testMutableStringer) String() string {
 	return string(*s)
 }
 
+type testMutableError struct {
+	message string
+}
+
+func (e *testMutableError) Error() string {
+	return e.message
+}
+
 type testSnapshotter []int
 
 func (s testSnapshotter) TakeSnapshot() interface{} {
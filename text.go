@@ -0,0 +1,310 @@
+package valf
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// valueText returns a human readable text representation of v.
+func valueText(v Value) string {
+	if v.bits.Type() == TypeString {
+		return v.vString
+	}
+
+	var tv textVisitor
+	v.AcceptVisitor(&tv)
+
+	return string(tv.buf)
+}
+
+// errorsText returns messages of errors in s, see nilText.
+func errorsText(s []error) []string {
+	text := make([]string, len(s))
+	for i, err := range s {
+		if err != nil {
+			text[i] = err.Error()
+		} else {
+			text[i] = nilText
+		}
+	}
+
+	return text
+}
+
+// stringersText returns text of Stringers in s, see nilText.
+func stringersText(s []fmt.Stringer) []string {
+	text := make([]string, len(s))
+	for i, item := range s {
+		if item != nil {
+			text[i] = item.String()
+		} else {
+			text[i] = nilText
+		}
+	}
+
+	return text
+}
+
+// textVisitor renders visited values to a text form.
+type textVisitor struct {
+	buf []byte
+}
+
+func (v *textVisitor) VisitNone() {}
+
+func (v *textVisitor) VisitAny(value interface{}) {
+	if value != nil {
+		v.buf = append(v.buf, fmt.Sprint(value)...)
+	}
+}
+
+func (v *textVisitor) VisitBool(value bool) {
+	v.buf = strconv.AppendBool(v.buf, value)
+}
+
+func (v *textVisitor) VisitInt(value int) {
+	v.buf = strconv.AppendInt(v.buf, int64(value), 10)
+}
+
+func (v *textVisitor) VisitInt8(value int8) {
+	v.buf = strconv.AppendInt(v.buf, int64(value), 10)
+}
+
+func (v *textVisitor) VisitInt16(value int16) {
+	v.buf = strconv.AppendInt(v.buf, int64(value), 10)
+}
+
+func (v *textVisitor) VisitInt32(value int32) {
+	v.buf = strconv.AppendInt(v.buf, int64(value), 10)
+}
+
+func (v *textVisitor) VisitInt64(value int64) {
+	v.buf = strconv.AppendInt(v.buf, value, 10)
+}
+
+func (v *textVisitor) VisitUint(value uint) {
+	v.buf = strconv.AppendUint(v.buf, uint64(value), 10)
+}
+
+func (v *textVisitor) VisitUint8(value uint8) {
+	v.buf = strconv.AppendUint(v.buf, uint64(value), 10)
+}
+
+func (v *textVisitor) VisitUint16(value uint16) {
+	v.buf = strconv.AppendUint(v.buf, uint64(value), 10)
+}
+
+func (v *textVisitor) VisitUint32(value uint32) {
+	v.buf = strconv.AppendUint(v.buf, uint64(value), 10)
+}
+
+func (v *textVisitor) VisitUint64(value uint64) {
+	v.buf = strconv.AppendUint(v.buf, value, 10)
+}
+
+func (v *textVisitor) VisitFloat32(value float32) {
+	v.buf = strconv.AppendFloat(v.buf, float64(value), 'g', -1, 32)
+}
+
+func (v *textVisitor) VisitFloat64(value float64) {
+	v.buf = strconv.AppendFloat(v.buf, value, 'g', -1, 64)
+}
+
+func (v *textVisitor) VisitDuration(value time.Duration) {
+	v.buf = append(v.buf, value.String()...)
+}
+
+func (v *textVisitor) VisitError(value error) {
+	if value != nil {
+		v.buf = append(v.buf, value.Error()...)
+	}
+}
+
+func (v *textVisitor) VisitTime(value time.Time) {
+	v.buf = value.AppendFormat(v.buf, time.RFC3339Nano)
+}
+
+func (v *textVisitor) VisitString(value string) {
+	v.buf = append(v.buf, value...)
+}
+
+func (v *textVisitor) VisitStrings(value []string) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.buf = append(v.buf, item...)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitBytes(value []byte) {
+	v.buf = append(v.buf, base64.StdEncoding.EncodeToString(value)...)
+}
+
+func (v *textVisitor) VisitBools(value []bool) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitBool(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitInts(value []int) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitInt(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitInts8(value []int8) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitInt8(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitInts16(value []int16) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitInt16(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitInts32(value []int32) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitInt32(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitInts64(value []int64) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitInt64(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitUints(value []uint) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitUint(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitUints8(value []uint8) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitUint8(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitUints16(value []uint16) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitUint16(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitUints32(value []uint32) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitUint32(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitUints64(value []uint64) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitUint64(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitFloats32(value []float32) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitFloat32(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitFloats64(value []float64) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitFloat64(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitDurations(value []time.Duration) {
+	v.buf = append(v.buf, '[')
+	for i, item := range value {
+		v.separate(i)
+		v.VisitDuration(item)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitArray(value ValueArray) {
+	v.buf = append(v.buf, '[')
+	if value != nil {
+		value.AcceptArrayItemVisitor(v)
+	}
+	v.buf = append(v.buf, ']')
+}
+
+func (v *textVisitor) VisitArrayItem(index int, value Value) {
+	v.separate(index)
+	value.AcceptVisitor(v)
+}
+
+func (v *textVisitor) VisitObject(value ValueObject) {
+	v.buf = append(v.buf, '{')
+	if value != nil {
+		value.AcceptObjectFieldVisitor(&textObjectFieldVisitor{v, 0})
+	}
+	v.buf = append(v.buf, '}')
+}
+
+func (v *textVisitor) separate(index int) {
+	if index != 0 {
+		v.buf = append(v.buf, ' ')
+	}
+}
+
+type textObjectFieldVisitor struct {
+	*textVisitor
+	index int
+}
+
+func (v *textObjectFieldVisitor) VisitObjectField(key string, value Value) {
+	v.separate(v.index)
+	v.index++
+	v.buf = append(v.buf, key...)
+	v.buf = append(v.buf, ':')
+	value.AcceptVisitor(v.textVisitor)
+}
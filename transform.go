@@ -0,0 +1,201 @@
+package valf
+
+import (
+	"time"
+)
+
+// Transformer is a composable transformation of a value tree. It is applied
+// lazily, so nothing is copied until the resulting value is visited or
+// snapshotted. The zero value is a valid Transformer which changes nothing.
+//
+// All methods return a new Transformer which applies an additional step
+// after the steps of the original one, so pipelines can be built declaratively:
+//
+//	t := valf.Transformer{}.
+//		RenameKey("msg", "message").
+//		DropNulls().
+//		ConvertType(valf.TypeDuration, valf.DurationAsMilliseconds)
+//
+// Functions passed to a Transformer should be pure since they may be called
+// more than once for the same value, e.g. to count object fields.
+type Transformer struct {
+	steps []transformStep
+}
+
+type transformStep struct {
+	field   func(path []string, key string, value Value) (string, Value, bool)
+	item    func(path []string, value Value) Value
+	filters bool
+}
+
+// Apply returns a Value which applies the transformation to v and all its nested values.
+func (t Transformer) Apply(v Value) Value {
+	if len(t.steps) == 0 {
+		return v
+	}
+
+	return mapRoot(t, v)
+}
+
+// Then returns a Transformer which applies steps of other after steps of t.
+func (t Transformer) Then(other Transformer) Transformer {
+	return Transformer{append(t.steps[:len(t.steps):len(t.steps)], other.steps...)}
+}
+
+// Map returns a Transformer which additionally replaces every value in the
+// tree including the root one and array items with the result of f.
+func (t Transformer) Map(f func(Value) Value) Transformer {
+	return t.withItemStep(func(_ []string, value Value) Value {
+		return f(value)
+	})
+}
+
+// ConvertType returns a Transformer which additionally replaces every value
+// of type vt in the tree with the result of f.
+func (t Transformer) ConvertType(vt Type, f func(Value) Value) Transformer {
+	return t.withItemStep(func(_ []string, value Value) Value {
+		if value.bits.Type() != vt {
+			return value
+		}
+
+		return f(value)
+	})
+}
+
+// MapFields returns a Transformer which additionally maps every object
+// field with f. The path contains keys of the objects enclosing the field,
+// arrays do not add path segments. The field is dropped if f returns false.
+func (t Transformer) MapFields(f func(path []string, key string, value Value) (string, Value, bool)) Transformer {
+	return t.withFieldStep(f, true)
+}
+
+// Filter returns a Transformer which additionally drops object fields for
+// which f returns false.
+func (t Transformer) Filter(f func(key string, value Value) bool) Transformer {
+	return t.withFieldStep(func(_ []string, key string, value Value) (string, Value, bool) {
+		return key, value, f(key, value)
+	}, true)
+}
+
+// RenameKey returns a Transformer which additionally renames object fields
+// with key from to key to.
+func (t Transformer) RenameKey(from, to string) Transformer {
+	return t.withFieldStep(func(_ []string, key string, value Value) (string, Value, bool) {
+		if key == from {
+			key = to
+		}
+
+		return key, value, true
+	}, false)
+}
+
+// RenameKeys returns a Transformer which additionally renames object fields
+// according to the mapping from old keys to new keys.
+func (t Transformer) RenameKeys(mapping map[string]string) Transformer {
+	return t.withFieldStep(func(_ []string, key string, value Value) (string, Value, bool) {
+		if renamed, ok := mapping[key]; ok {
+			key = renamed
+		}
+
+		return key, value, true
+	}, false)
+}
+
+// DropKeys returns a Transformer which additionally drops object fields
+// with any of the given keys.
+func (t Transformer) DropKeys(keys ...string) Transformer {
+	return t.Filter(func(key string, _ Value) bool {
+		for _, k := range keys {
+			if k == key {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// DropNulls returns a Transformer which additionally drops object fields
+// with absent or null values, see Value.IsNull.
+func (t Transformer) DropNulls() Transformer {
+	return t.Filter(func(_ string, value Value) bool {
+		return value.bits.Type() != TypeNone && !value.IsNull()
+	})
+}
+
+// DropEmpty returns a Transformer which additionally drops object fields
+// with empty values, see Value.IsZero.
+func (t Transformer) DropEmpty() Transformer {
+	return t.Filter(func(_ string, value Value) bool {
+		return !value.IsZero()
+	})
+}
+
+func (t Transformer) withItemStep(f func(path []string, value Value) Value) Transformer {
+	return Transformer{append(t.steps[:len(t.steps):len(t.steps)], transformStep{item: f})}
+}
+
+func (t Transformer) withFieldStep(f func(path []string, key string, value Value) (string, Value, bool), filters bool) Transformer {
+	return Transformer{append(t.steps[:len(t.steps):len(t.steps)], transformStep{field: f, filters: filters})}
+}
+
+func (t Transformer) mapField(path []string, key string, value Value) (string, Value, bool) {
+	for _, step := range t.steps {
+		if step.field != nil {
+			var keep bool
+			key, value, keep = step.field(path, key, value)
+			if !keep {
+				return key, value, false
+			}
+		} else {
+			value = step.item(path, value)
+		}
+	}
+
+	return key, value, true
+}
+
+func (t Transformer) mapItem(path []string, value Value) Value {
+	for _, step := range t.steps {
+		if step.item != nil {
+			value = step.item(path, value)
+		}
+	}
+
+	return value
+}
+
+func (t Transformer) filtersFields() bool {
+	for _, step := range t.steps {
+		if step.filters {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DurationAsMilliseconds converts a Duration value to a Float64 value
+// holding the number of milliseconds. Other values are returned as is.
+// It is intended to be used with Transformer.ConvertType.
+func DurationAsMilliseconds(v Value) Value {
+	if v.bits.Type() != TypeDuration {
+		return v
+	}
+
+	return Float64(float64(v.vInt) / float64(time.Millisecond))
+}
+
+// ErrorAsString converts an Error value to a String value holding the error
+// message. Other values are returned as is.
+// It is intended to be used with Transformer.ConvertType.
+func ErrorAsString(v Value) Value {
+	if v.bits.Type() != TypeError {
+		return v
+	}
+	if v.vAny == nil {
+		return Value{bits: bits(TypeAny) | bitsConst}
+	}
+
+	return String(v.vAny.(error).Error())
+}
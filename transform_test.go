@@ -0,0 +1,89 @@
+package valf
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformer(t *testing.T) {
+	tr := Transformer{}.
+		RenameKey("msg", "message").
+		RenameKeys(map[string]string{"ts": "time"}).
+		DropKeys("internal").
+		DropNulls().
+		ConvertType(TypeDuration, DurationAsMilliseconds).
+		ConvertType(TypeError, ErrorAsString)
+
+	value := tr.Apply(Object(mockObject{
+		"msg":      String("hello"),
+		"ts":       Int(1),
+		"internal": Int(2),
+		"null":     Any(nil),
+		"err":      Error(errors.New("failure")),
+		"nested":   Object(mockObject{"elapsed": Duration(1500 * time.Microsecond), "none": Error(nil)}),
+		"list":     Array(mockArray{Duration(time.Second)}),
+	}))
+
+	visitor := newMockObjectVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, 5, visitor.count)
+	require.Equal(t, String("hello"), visitor.value["message"])
+	require.Equal(t, Int(1), visitor.value["time"])
+	require.Equal(t, String("failure"), visitor.value["err"])
+	require.Equal(t, 5, len(visitor.value))
+
+	nested := newMockObjectVisitor(t)
+	visitor.value["nested"].AcceptVisitor(nested)
+	require.Equal(t, 1, nested.count)
+	require.Equal(t, map[string]Value{"elapsed": Float64(1.5)}, nested.value)
+
+	list := newMockArrayVisitor(t)
+	visitor.value["list"].AcceptVisitor(list)
+	require.Equal(t, []Value{Float64(1000)}, list.value)
+}
+
+func TestTransformerMapFields(t *testing.T) {
+	tr := Transformer{}.MapFields(func(path []string, key string, value Value) (string, Value, bool) {
+		if len(path) == 1 && path[0] == "user" {
+			return "user." + key, value, key != "password"
+		}
+
+		return key, value, true
+	})
+
+	value := tr.Apply(Object(mockObject{
+		"user": Object(mockObject{"name": String("john"), "password": String("secret")}),
+	}))
+
+	visitor := newMockObjectVisitor(t)
+	value.AcceptVisitor(visitor)
+
+	user := newMockObjectVisitor(t)
+	visitor.value["user"].AcceptVisitor(user)
+	require.Equal(t, 1, user.count)
+	require.Equal(t, map[string]Value{"user.name": String("john")}, user.value)
+}
+
+func TestTransformerMapAndThen(t *testing.T) {
+	double := Transformer{}.Map(func(v Value) Value {
+		if v.Type() == TypeInt {
+			return Int(int(v.vInt) * 2)
+		}
+
+		return v
+	})
+	tr := double.Then(double)
+
+	require.Equal(t, Int(4), tr.Apply(Int(1)))
+	require.Equal(t, Int(2), double.Apply(Int(1)))
+	require.Equal(t, Int(1), Transformer{}.Apply(Int(1)))
+
+	value := tr.Apply(Array(mockArray{Int(1), Int(2)})).Snapshot()
+	visitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, []Value{Int(4), Int(8)}, visitor.value)
+}
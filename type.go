@@ -1,5 +1,7 @@
 package valf
 
+import "fmt"
+
 // Type defines the value type stored in the Value.
 type Type byte
 
@@ -46,4 +48,101 @@ const (
 	TypeObject
 	TypeStringer
 	TypeFormatter
+	TypeLazy
+	TypeBigInt
+	TypeBigFloat
+	TypeBigRat
+	TypeDecimal
+	TypeComplex64
+	TypeComplex128
+	TypeUintptr
+	TypeComplexes64
+	TypeComplexes128
+	TypeUintptrs
+	TypeIP
+	TypeIPPort
+	TypeIPPrefix
+	TypeMAC
+	TypeUUID
+	TypeRaw
+	TypeEnum
+	TypeAnnotated
+	TypeNull
+	TypeTimes
+	TypeErrors
+	TypeStringers
 )
+
+var typeNames = [...]string{
+	TypeNone:         "none",
+	TypeAny:          "any",
+	TypeBool:         "bool",
+	TypeInt:          "int",
+	TypeInt8:         "int8",
+	TypeInt16:        "int16",
+	TypeInt32:        "int32",
+	TypeInt64:        "int64",
+	TypeUint:         "uint",
+	TypeUint8:        "uint8",
+	TypeUint16:       "uint16",
+	TypeUint32:       "uint32",
+	TypeUint64:       "uint64",
+	TypeFloat32:      "float32",
+	TypeFloat64:      "float64",
+	TypeDuration:     "duration",
+	TypeError:        "error",
+	TypeTime:         "time",
+	TypeString:       "string",
+	TypeBytes:        "bytes",
+	TypeBools:        "bools",
+	TypeInts:         "ints",
+	TypeInts8:        "ints8",
+	TypeInts16:       "ints16",
+	TypeInts32:       "ints32",
+	TypeInts64:       "ints64",
+	TypeUints:        "uints",
+	TypeUints8:       "uints8",
+	TypeUints16:      "uints16",
+	TypeUints32:      "uints32",
+	TypeUints64:      "uints64",
+	TypeFloats32:     "floats32",
+	TypeFloats64:     "floats64",
+	TypeDurations:    "durations",
+	TypeStrings:      "strings",
+	TypeArray:        "array",
+	TypeObject:       "object",
+	TypeStringer:     "stringer",
+	TypeFormatter:    "formatter",
+	TypeLazy:         "lazy",
+	TypeBigInt:       "bigint",
+	TypeBigFloat:     "bigfloat",
+	TypeBigRat:       "bigrat",
+	TypeDecimal:      "decimal",
+	TypeComplex64:    "complex64",
+	TypeComplex128:   "complex128",
+	TypeUintptr:      "uintptr",
+	TypeComplexes64:  "complexes64",
+	TypeComplexes128: "complexes128",
+	TypeUintptrs:     "uintptrs",
+	TypeIP:           "ip",
+	TypeIPPort:       "ipport",
+	TypeIPPrefix:     "ipprefix",
+	TypeMAC:          "mac",
+	TypeUUID:         "uuid",
+	TypeRaw:          "raw",
+	TypeEnum:         "enum",
+	TypeAnnotated:    "annotated",
+	TypeNull:         "null",
+	TypeTimes:        "times",
+	TypeErrors:       "errors",
+	TypeStringers:    "stringers",
+}
+
+// String returns the name of the type, e.g. "bool" for TypeBool.
+func (t Type) String() string {
+	if int(t) < len(typeNames) {
+		return typeNames[t]
+	}
+
+	return fmt.Sprintf("Type(%d)", byte(t))
+}
@@ -0,0 +1,17 @@
+package valf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeString(t *testing.T) {
+	require.Equal(t, "bool", TypeBool.String())
+	require.Equal(t, "ipprefix", TypeIPPrefix.String())
+	require.Equal(t, "Type(200)", Type(200).String())
+
+	for tt := TypeNone; tt <= TypeStringers; tt++ {
+		require.NotEmpty(t, tt.String())
+	}
+}
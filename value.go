@@ -1,18 +1,27 @@
 package valf
 
 import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
-	"reflect"
+	"math/big"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 )
 
 // Value holds data of a specific type.
 type Value struct {
+	owned   ownership
 	bits    bits
 	vAny    interface{}
 	vInt    int64
+	vExt    int64
 	vBytes  []byte
 	vString string
 }
@@ -35,9 +44,21 @@ func (v Value) Snapshot() Value {
 	return v
 }
 
+// Resolve returns the value produced by a lazy value stored in v, see Lazy.
+// Other values are returned as is.
+func (v Value) Resolve() Value {
+	if v.bits.Type() != TypeLazy {
+		return v
+	}
+
+	return resolveLazy(v.vAny.(Valuer))
+}
+
 // AcceptVisitor interprets Value data according to its type and calls appropriate
 // Visitor method.
 func (v Value) AcceptVisitor(visitor Visitor) {
+	checkOwned(v)
+
 	switch v.bits.Type() {
 	case TypeNone:
 		visitor.VisitNone()
@@ -99,6 +120,28 @@ func (v Value) AcceptVisitor(visitor Visitor) {
 		}
 	case TypeFormatter:
 		visitor.VisitString(fmt.Sprintf(v.vString, v.vAny))
+	case TypeLazy:
+		v.Resolve().AcceptVisitor(visitor)
+	case TypeBigInt, TypeBigFloat, TypeBigRat, TypeDecimal:
+		v.acceptBigVisitor(visitor)
+	case TypeComplex64, TypeComplex128, TypeComplexes64, TypeComplexes128:
+		v.acceptComplexVisitor(visitor)
+	case TypeUintptr, TypeUintptrs:
+		v.acceptUintptrVisitor(visitor)
+	case TypeIP, TypeIPPort, TypeIPPrefix, TypeMAC:
+		v.acceptNetVisitor(visitor)
+	case TypeUUID:
+		v.acceptUUIDVisitor(visitor)
+	case TypeRaw:
+		v.acceptRawVisitor(visitor)
+	case TypeEnum:
+		v.acceptEnumVisitor(visitor)
+	case TypeAnnotated:
+		v.acceptAnnotatedVisitor(visitor)
+	case TypeNull:
+		v.acceptNullVisitor(visitor)
+	case TypeTimes, TypeErrors, TypeStringers:
+		v.acceptSliceVisitor(visitor)
 	case TypeBytes:
 		visitor.VisitBytes(v.vBytes)
 	case TypeString:
@@ -214,6 +257,73 @@ func Duration(v time.Duration) Value {
 	return Value{bits: bits(TypeDuration) | bitsConst, vInt: int64(v)}
 }
 
+// Complex64 returns a new Value with the given complex64.
+func Complex64(v complex64) Value {
+	re := uint64(math.Float32bits(real(v)))
+	im := uint64(math.Float32bits(imag(v)))
+
+	return Value{bits: bits(TypeComplex64) | bitsConst, vInt: int64(re | im<<32)}
+}
+
+// Complex128 returns a new Value with the given complex128.
+func Complex128(v complex128) Value {
+	re := int64(math.Float64bits(real(v)))
+	im := int64(math.Float64bits(imag(v)))
+
+	return Value{bits: bits(TypeComplex128) | bitsConst, vInt: re, vExt: im}
+}
+
+// Uintptr returns a new Value with the given uintptr.
+func Uintptr(v uintptr) Value {
+	return Value{bits: bits(TypeUintptr) | bitsConst, vInt: int64(v)}
+}
+
+// IP returns a new Value with the given IP address.
+func IP(v netip.Addr) Value {
+	return ipValue(TypeIP, v, 0)
+}
+
+// IPPort returns a new Value with the given IP address and port.
+func IPPort(v netip.AddrPort) Value {
+	return ipValue(TypeIPPort, v.Addr(), v.Port())
+}
+
+// IPPrefix returns a new Value with the given IP prefix.
+func IPPrefix(v netip.Prefix) Value {
+	return ipValue(TypeIPPrefix, v.Addr(), uint16(v.Bits()))
+}
+
+// MAC returns a new Value with the given hardware address.
+func MAC(v net.HardwareAddr) Value {
+	if v == nil {
+		return ConstMAC(v)
+	}
+
+	return Value{bits: bits(TypeMAC), vBytes: v}
+}
+
+// ConstMAC returns a new Value with the given hardware address.
+//
+// Call ConstMAC if your address is const. It has significantly less impact
+// on the calling goroutine.
+func ConstMAC(v net.HardwareAddr) Value {
+	return Value{bits: bits(TypeMAC) | bitsConst, vBytes: v}
+}
+
+// UUID returns a new Value with the given UUID.
+func UUID(v [16]byte) Value {
+	return Value{
+		bits: bits(TypeUUID) | bitsConst,
+		vInt: int64(binary.BigEndian.Uint64(v[:8])),
+		vExt: int64(binary.BigEndian.Uint64(v[8:])),
+	}
+}
+
+// Enum returns a new Value with the given enum value having both a number and a name.
+func Enum(value int64, name string) Value {
+	return Value{bits: bits(TypeEnum) | bitsConst, vInt: value, vString: name}
+}
+
 // Bytes returns a new Value with the given slice of bytes.
 func Bytes(v []byte) Value {
 	return Value{bits: bits(TypeBytes), vBytes: v}
@@ -304,6 +414,21 @@ func Durations(v []time.Duration) Value {
 	return Value{bits: bits(TypeDurations), vBytes: *(*[]byte)(unsafe.Pointer(&v))}
 }
 
+// Complexes64 returns a new Value with the given slice of 64-bit complex numbers.
+func Complexes64(v []complex64) Value {
+	return Value{bits: bits(TypeComplexes64), vBytes: *(*[]byte)(unsafe.Pointer(&v))}
+}
+
+// Complexes128 returns a new Value with the given slice of 128-bit complex numbers.
+func Complexes128(v []complex128) Value {
+	return Value{bits: bits(TypeComplexes128), vBytes: *(*[]byte)(unsafe.Pointer(&v))}
+}
+
+// Uintptrs returns a new Value with the given slice of uintptrs.
+func Uintptrs(v []uintptr) Value {
+	return Value{bits: bits(TypeUintptrs), vBytes: *(*[]byte)(unsafe.Pointer(&v))}
+}
+
 // ConstBytes returns a new Value with the given slice of bytes.
 //
 // Call ConstBytes if your array is const. It has significantly less impact
@@ -439,8 +564,89 @@ func ConstDurations(v []time.Duration) Value {
 	return Value{bits: bits(TypeDurations) | bitsConst, vBytes: *(*[]byte)(unsafe.Pointer(&v))}
 }
 
+// Times returns a new Value with the given slice of times.
+func Times(v []time.Time) Value {
+	return Value{bits: bits(TypeTimes), vAny: v}
+}
+
+// ConstTimes returns a new Value with the given slice of times.
+//
+// Call ConstTimes if your slice is const. It has significantly less impact
+// on the calling goroutine.
+func ConstTimes(v []time.Time) Value {
+	return Value{bits: bits(TypeTimes) | bitsConst, vAny: v}
+}
+
+// Errors returns a new Value with the given slice of errors.
+// Snapshot renders the errors to strings.
+func Errors(v []error) Value {
+	return Value{bits: bits(TypeErrors), vAny: v}
+}
+
+// ConstErrors returns a new Value with the given slice of errors.
+//
+// Call ConstErrors if your slice and errors are const. It has significantly
+// less impact on the calling goroutine.
+func ConstErrors(v []error) Value {
+	return Value{bits: bits(TypeErrors) | bitsConst, vAny: v}
+}
+
+// Stringers returns a new Value with the given slice of Stringers.
+// Snapshot renders the Stringers to strings.
+func Stringers(v []fmt.Stringer) Value {
+	return Value{bits: bits(TypeStringers), vAny: v}
+}
+
+// ConstStringers returns a new Value with the given slice of Stringers.
+//
+// Call ConstStringers if your slice and Stringers are const. It has
+// significantly less impact on the calling goroutine.
+func ConstStringers(v []fmt.Stringer) Value {
+	return Value{bits: bits(TypeStringers) | bitsConst, vAny: v}
+}
+
+// ConstComplexes64 returns a new Value with the given slice of 64-bit complex numbers.
+//
+// Call ConstComplexes64 if your array is const. It has significantly less impact
+// on the calling goroutine.
+func ConstComplexes64(v []complex64) Value {
+	return Value{bits: bits(TypeComplexes64) | bitsConst, vBytes: *(*[]byte)(unsafe.Pointer(&v))}
+}
+
+// ConstComplexes128 returns a new Value with the given slice of 128-bit complex numbers.
+//
+// Call ConstComplexes128 if your array is const. It has significantly less impact
+// on the calling goroutine.
+func ConstComplexes128(v []complex128) Value {
+	return Value{bits: bits(TypeComplexes128) | bitsConst, vBytes: *(*[]byte)(unsafe.Pointer(&v))}
+}
+
+// ConstUintptrs returns a new Value with the given slice of uintptrs.
+//
+// Call ConstUintptrs if your array is const. It has significantly less impact
+// on the calling goroutine.
+func ConstUintptrs(v []uintptr) Value {
+	return Value{bits: bits(TypeUintptrs) | bitsConst, vBytes: *(*[]byte)(unsafe.Pointer(&v))}
+}
+
 // Error returns a new Value with the given error.
+// Snapshot replaces the error with an immutable copy which keeps its message,
+// type name, causes and fields, see ErrorDetails. The copy matches the
+// original comparable error in errors.Is and so keeps it alive, but
+// errors.As finds only errors of types without pointers, slices, maps,
+// interfaces, channels and functions. Use ConstError to keep the error itself.
 func Error(v error) Value {
+	if v == nil {
+		return ConstError(v)
+	}
+
+	return Value{bits: bits(TypeError), vAny: v}
+}
+
+// ConstError returns a new Value with the given error.
+// Call ConstError if your error is const. It has significantly less
+// impact on the calling goroutine.
+func ConstError(v error) Value {
 	return Value{bits: bits(TypeError) | bitsConst, vAny: v}
 }
 
@@ -526,6 +732,130 @@ func ConstFormatterRepr(v interface{}) Value {
 	return ConstFormatter("%#v", v)
 }
 
+// BigInt returns a new Value with the given arbitrary-precision integer.
+func BigInt(v *big.Int) Value {
+	if v == nil {
+		return ConstBigInt(v)
+	}
+
+	return Value{bits: bits(TypeBigInt), vAny: v}
+}
+
+// ConstBigInt returns a new Value with the given arbitrary-precision integer.
+//
+// Call ConstBigInt if your number is const. It has significantly less impact
+// on the calling goroutine.
+func ConstBigInt(v *big.Int) Value {
+	if v == nil {
+		return Value{bits: bits(TypeBigInt) | bitsConst}
+	}
+
+	return Value{bits: bits(TypeBigInt) | bitsConst, vAny: v}
+}
+
+// BigFloat returns a new Value with the given arbitrary-precision float.
+func BigFloat(v *big.Float) Value {
+	if v == nil {
+		return ConstBigFloat(v)
+	}
+
+	return Value{bits: bits(TypeBigFloat), vAny: v}
+}
+
+// ConstBigFloat returns a new Value with the given arbitrary-precision float.
+//
+// Call ConstBigFloat if your number is const. It has significantly less impact
+// on the calling goroutine.
+func ConstBigFloat(v *big.Float) Value {
+	if v == nil {
+		return Value{bits: bits(TypeBigFloat) | bitsConst}
+	}
+
+	return Value{bits: bits(TypeBigFloat) | bitsConst, vAny: v}
+}
+
+// BigRat returns a new Value with the given arbitrary-precision rational number.
+func BigRat(v *big.Rat) Value {
+	if v == nil {
+		return ConstBigRat(v)
+	}
+
+	return Value{bits: bits(TypeBigRat), vAny: v}
+}
+
+// ConstBigRat returns a new Value with the given arbitrary-precision rational number.
+//
+// Call ConstBigRat if your number is const. It has significantly less impact
+// on the calling goroutine.
+func ConstBigRat(v *big.Rat) Value {
+	if v == nil {
+		return Value{bits: bits(TypeBigRat) | bitsConst}
+	}
+
+	return Value{bits: bits(TypeBigRat) | bitsConst, vAny: v}
+}
+
+// Decimal returns a new Value with the given decimal number which is equal
+// to coefficient * 10^exponent.
+func Decimal(coefficient *big.Int, exponent int32) Value {
+	if coefficient == nil {
+		return ConstDecimal(coefficient, exponent)
+	}
+
+	return Value{bits: bits(TypeDecimal), vAny: coefficient, vInt: int64(exponent)}
+}
+
+// ConstDecimal returns a new Value with the given decimal number which is
+// equal to coefficient * 10^exponent.
+//
+// Call ConstDecimal if your coefficient is const. It has significantly less
+// impact on the calling goroutine.
+func ConstDecimal(coefficient *big.Int, exponent int32) Value {
+	if coefficient == nil {
+		return Value{bits: bits(TypeDecimal) | bitsConst, vInt: int64(exponent)}
+	}
+
+	return Value{bits: bits(TypeDecimal) | bitsConst, vAny: coefficient, vInt: int64(exponent)}
+}
+
+// DecimalNumber is the interface implemented by decimal number types
+// like github.com/shopspring/decimal.Decimal. Any and ConstAny recognize
+// types implementing it and return Decimal values.
+type DecimalNumber interface {
+	Coefficient() *big.Int
+	Exponent() int32
+}
+
+// Enumer is the interface implemented by enum-like types having both
+// a number and a symbolic name. Any and ConstAny recognize types
+// implementing it and return Enum values instead of Stringer values.
+type Enumer interface {
+	fmt.Stringer
+	EnumValue() int64
+}
+
+// Lazy returns a new Value which is evaluated by calling f only when it is
+// visited or snapshotted. Snapshot calls f exactly once and stores its result.
+//
+// Use Lazy for values which are expensive to build and may be not needed at all.
+func Lazy(f func() Value) Value {
+	if f == nil {
+		return Value{bits: bits(TypeAny) | bitsConst}
+	}
+
+	return LazyValuer(lazyFunc(f))
+}
+
+// LazyValuer returns a new Value which is evaluated by calling v.Value()
+// only when it is visited or snapshotted, see Lazy.
+func LazyValuer(v Valuer) Value {
+	if v == nil {
+		return Value{bits: bits(TypeAny) | bitsConst}
+	}
+
+	return Value{bits: bits(TypeLazy), vAny: v}
+}
+
 // Any returns a new Value with the given value of any type. It tries
 // to choose the best way to represent value a Value.
 //
@@ -537,6 +867,8 @@ func Any(v interface{}) Value {
 	}
 
 	switch rv := v.(type) {
+	case Valuer:
+		return LazyValuer(rv)
 	case bool:
 		return Bool(rv)
 	case int:
@@ -563,6 +895,12 @@ func Any(v interface{}) Value {
 		return Float64(rv)
 	case float32:
 		return Float32(rv)
+	case complex64:
+		return Complex64(rv)
+	case complex128:
+		return Complex128(rv)
+	case uintptr:
+		return Uintptr(rv)
 	case time.Time:
 		return Time(rv)
 	case time.Duration:
@@ -573,6 +911,32 @@ func Any(v interface{}) Value {
 		return Array(rv)
 	case ValueObject:
 		return Object(rv)
+	case netip.Addr:
+		return IP(rv)
+	case netip.AddrPort:
+		return IPPort(rv)
+	case netip.Prefix:
+		return IPPrefix(rv)
+	case net.HardwareAddr:
+		return MAC(rv)
+	case json.RawMessage:
+		return RawJSON(rv)
+	case *bool:
+		return BoolPtr(rv)
+	case *int:
+		return IntPtr(rv)
+	case *int64:
+		return Int64Ptr(rv)
+	case *uint64:
+		return Uint64Ptr(rv)
+	case *float64:
+		return Float64Ptr(rv)
+	case *string:
+		return StringPtr(rv)
+	case *time.Duration:
+		return DurationPtr(rv)
+	case *time.Time:
+		return TimePtr(rv)
 	case []byte:
 		return Bytes(rv)
 	case []string:
@@ -601,47 +965,56 @@ func Any(v interface{}) Value {
 		return Floats64(rv)
 	case []float32:
 		return Floats32(rv)
+	case []complex64:
+		return Complexes64(rv)
+	case []complex128:
+		return Complexes128(rv)
+	case []uintptr:
+		return Uintptrs(rv)
 	case []time.Duration:
 		return Durations(rv)
+	case []time.Time:
+		return Times(rv)
+	case []error:
+		return Errors(rv)
+	case []fmt.Stringer:
+		return Stringers(rv)
+	case map[string]string:
+		return StringMap(rv)
+	case map[string]int:
+		return IntMap(rv)
+	case map[string]interface{}:
+		return Map(rv)
+	case map[string]Value:
+		return ValueMap(rv)
+	case []Value:
+		return Values(rv)
+	case []interface{}:
+		return AnySlice(rv)
+	case Value:
+		return rv
+	case *big.Int:
+		return BigInt(rv)
+	case *big.Float:
+		return BigFloat(rv)
+	case *big.Rat:
+		return BigRat(rv)
+	case DecimalNumber:
+		return Decimal(rv.Coefficient(), rv.Exponent())
 	case string:
 		return String(rv)
+	case Enumer:
+		return Enum(rv.EnumValue(), rv.String())
 	case fmt.Stringer:
+		if u, ok := anyUUID(v); ok {
+			return u
+		}
+
 		return Stringer(rv)
 
 	default:
-		switch reflect.TypeOf(rv).Kind() {
-		case reflect.String:
-			return String(reflect.ValueOf(rv).String())
-		case reflect.Bool:
-			return Bool(reflect.ValueOf(rv).Bool())
-		case reflect.Int:
-			return Int(int(reflect.ValueOf(rv).Int()))
-		case reflect.Int8:
-			return Int8(int8(reflect.ValueOf(rv).Int()))
-		case reflect.Int16:
-			return Int16(int16(reflect.ValueOf(rv).Int()))
-		case reflect.Int32:
-			return Int32(int32(reflect.ValueOf(rv).Int()))
-		case reflect.Int64:
-			return Int64(int64(reflect.ValueOf(rv).Int()))
-		case reflect.Uint:
-			return Uint(uint(reflect.ValueOf(rv).Uint()))
-		case reflect.Uint8:
-			return Uint8(uint8(reflect.ValueOf(rv).Uint()))
-		case reflect.Uint16:
-			return Uint16(uint16(reflect.ValueOf(rv).Uint()))
-		case reflect.Uint32:
-			return Uint32(uint32(reflect.ValueOf(rv).Uint()))
-		case reflect.Uint64:
-			return Uint64(uint64(reflect.ValueOf(rv).Uint()))
-		case reflect.Float32:
-			return Float32(float32(reflect.ValueOf(rv).Float()))
-		case reflect.Float64:
-			return Float64(float64(reflect.ValueOf(rv).Float()))
-		}
+		return anyOfType(rv, false)
 	}
-
-	return Value{bits: bits(TypeAny), vAny: v}
 }
 
 // ConstAny returns a new Value with the given value of any type. It tries
@@ -653,6 +1026,8 @@ func ConstAny(v interface{}) Value {
 	}
 
 	switch rv := v.(type) {
+	case Valuer:
+		return LazyValuer(rv)
 	case bool:
 		return Bool(rv)
 	case int:
@@ -679,16 +1054,48 @@ func ConstAny(v interface{}) Value {
 		return Float64(rv)
 	case float32:
 		return Float32(rv)
+	case complex64:
+		return Complex64(rv)
+	case complex128:
+		return Complex128(rv)
+	case uintptr:
+		return Uintptr(rv)
 	case time.Time:
 		return Time(rv)
 	case time.Duration:
 		return Duration(rv)
 	case error:
-		return Error(rv)
+		return ConstError(rv)
 	case ValueArray:
 		return ConstArray(rv)
 	case ValueObject:
 		return ConstObject(rv)
+	case netip.Addr:
+		return IP(rv)
+	case netip.AddrPort:
+		return IPPort(rv)
+	case netip.Prefix:
+		return IPPrefix(rv)
+	case net.HardwareAddr:
+		return ConstMAC(rv)
+	case json.RawMessage:
+		return ConstRawJSON(rv)
+	case *bool:
+		return BoolPtr(rv)
+	case *int:
+		return IntPtr(rv)
+	case *int64:
+		return Int64Ptr(rv)
+	case *uint64:
+		return Uint64Ptr(rv)
+	case *float64:
+		return Float64Ptr(rv)
+	case *string:
+		return StringPtr(rv)
+	case *time.Duration:
+		return DurationPtr(rv)
+	case *time.Time:
+		return TimePtr(rv)
 	case []byte:
 		return ConstBytes(rv)
 	case []string:
@@ -717,54 +1124,429 @@ func ConstAny(v interface{}) Value {
 		return ConstFloats64(rv)
 	case []float32:
 		return ConstFloats32(rv)
+	case []complex64:
+		return ConstComplexes64(rv)
+	case []complex128:
+		return ConstComplexes128(rv)
+	case []uintptr:
+		return ConstUintptrs(rv)
 	case []time.Duration:
 		return ConstDurations(rv)
+	case []time.Time:
+		return ConstTimes(rv)
+	case []error:
+		return ConstErrors(rv)
+	case []fmt.Stringer:
+		return ConstStringers(rv)
+	case map[string]string:
+		return ConstStringMap(rv)
+	case map[string]int:
+		return ConstIntMap(rv)
+	case map[string]interface{}:
+		return ConstMap(rv)
+	case map[string]Value:
+		return ConstValueMap(rv)
+	case []Value:
+		return ConstValues(rv)
+	case []interface{}:
+		return ConstAnySlice(rv)
+	case Value:
+		return rv
+	case *big.Int:
+		return ConstBigInt(rv)
+	case *big.Float:
+		return ConstBigFloat(rv)
+	case *big.Rat:
+		return ConstBigRat(rv)
+	case DecimalNumber:
+		return ConstDecimal(rv.Coefficient(), rv.Exponent())
 	case string:
 		return String(rv)
+	case Enumer:
+		return Enum(rv.EnumValue(), rv.String())
 	case fmt.Stringer:
+		if u, ok := anyUUID(v); ok {
+			return u
+		}
+
 		return ConstStringer(rv)
 
 	default:
-		switch reflect.TypeOf(rv).Kind() {
-		case reflect.String:
-			return String(reflect.ValueOf(rv).String())
-		case reflect.Bool:
-			return Bool(reflect.ValueOf(rv).Bool())
-		case reflect.Int:
-			return Int(int(reflect.ValueOf(rv).Int()))
-		case reflect.Int8:
-			return Int8(int8(reflect.ValueOf(rv).Int()))
-		case reflect.Int16:
-			return Int16(int16(reflect.ValueOf(rv).Int()))
-		case reflect.Int32:
-			return Int32(int32(reflect.ValueOf(rv).Int()))
-		case reflect.Int64:
-			return Int64(int64(reflect.ValueOf(rv).Int()))
-		case reflect.Uint:
-			return Uint(uint(reflect.ValueOf(rv).Uint()))
-		case reflect.Uint8:
-			return Uint8(uint8(reflect.ValueOf(rv).Uint()))
-		case reflect.Uint16:
-			return Uint16(uint16(reflect.ValueOf(rv).Uint()))
-		case reflect.Uint32:
-			return Uint32(uint32(reflect.ValueOf(rv).Uint()))
-		case reflect.Uint64:
-			return Uint64(uint64(reflect.ValueOf(rv).Uint()))
-		case reflect.Float32:
-			return Float32(float32(reflect.ValueOf(rv).Float()))
-		case reflect.Float64:
-			return Float64(float64(reflect.ValueOf(rv).Float()))
+		return anyOfType(rv, true)
+	}
+}
+
+func (v Value) acceptBigVisitor(visitor Visitor) {
+	if v.vAny == nil {
+		visitor.VisitAny(nil)
+
+		return
+	}
+
+	bv, ok := visitor.(BigVisitor)
+	switch v.bits.Type() {
+	case TypeBigInt:
+		if ok {
+			bv.VisitBigInt(v.vAny.(*big.Int))
+		} else {
+			visitor.VisitString(v.vAny.(*big.Int).String())
+		}
+	case TypeBigFloat:
+		if ok {
+			bv.VisitBigFloat(v.vAny.(*big.Float))
+		} else {
+			visitor.VisitString(v.vAny.(*big.Float).Text('g', -1))
+		}
+	case TypeBigRat:
+		if ok {
+			bv.VisitBigRat(v.vAny.(*big.Rat))
+		} else {
+			visitor.VisitString(v.vAny.(*big.Rat).RatString())
+		}
+	case TypeDecimal:
+		if ok {
+			bv.VisitDecimal(v.vAny.(*big.Int), int32(v.vInt))
+		} else {
+			visitor.VisitString(FormatDecimal(v.vAny.(*big.Int), int32(v.vInt)))
 		}
 	}
+}
 
-	return Value{bits: bits(TypeAny) | bitsConst, vAny: v}
+func (v Value) acceptComplexVisitor(visitor Visitor) {
+	cv, ok := visitor.(ComplexVisitor)
+	switch v.bits.Type() {
+	case TypeComplex64:
+		c := complex(math.Float32frombits(uint32(v.vInt)), math.Float32frombits(uint32(uint64(v.vInt)>>32)))
+		if ok {
+			cv.VisitComplex64(c)
+		} else {
+			visitor.VisitString(strconv.FormatComplex(complex128(c), 'g', -1, 64))
+		}
+	case TypeComplex128:
+		c := complex(math.Float64frombits(uint64(v.vInt)), math.Float64frombits(uint64(v.vExt)))
+		if ok {
+			cv.VisitComplex128(c)
+		} else {
+			visitor.VisitString(strconv.FormatComplex(c, 'g', -1, 128))
+		}
+	case TypeComplexes64:
+		s := *(*[]complex64)(unsafe.Pointer(&v.vBytes))
+		if ok {
+			cv.VisitComplexes64(s)
+		} else {
+			ss := make([]string, len(s))
+			for i, c := range s {
+				ss[i] = strconv.FormatComplex(complex128(c), 'g', -1, 64)
+			}
+			visitor.VisitStrings(ss)
+		}
+	case TypeComplexes128:
+		s := *(*[]complex128)(unsafe.Pointer(&v.vBytes))
+		if ok {
+			cv.VisitComplexes128(s)
+		} else {
+			ss := make([]string, len(s))
+			for i, c := range s {
+				ss[i] = strconv.FormatComplex(c, 'g', -1, 128)
+			}
+			visitor.VisitStrings(ss)
+		}
+	}
+}
+
+func (v Value) acceptUintptrVisitor(visitor Visitor) {
+	uv, ok := visitor.(UintptrVisitor)
+	switch v.bits.Type() {
+	case TypeUintptr:
+		if ok {
+			uv.VisitUintptr(uintptr(v.vInt))
+		} else {
+			visitor.VisitUint64(uint64(v.vInt))
+		}
+	case TypeUintptrs:
+		if ok {
+			uv.VisitUintptrs(*(*[]uintptr)(unsafe.Pointer(&v.vBytes)))
+		} else {
+			visitor.VisitUints(*(*[]uint)(unsafe.Pointer(&v.vBytes)))
+		}
+	}
 }
 
-type bits byte
+// Slices of uintptr are visited as slices of uint, so the types must have
+// the same size. The declarations fail to compile otherwise.
+var (
+	_ [unsafe.Sizeof(uint(0)) - unsafe.Sizeof(uintptr(0))]struct{}
+	_ [unsafe.Sizeof(uintptr(0)) - unsafe.Sizeof(uint(0))]struct{}
+)
+
+// Address families stored in flags of IP values.
+const (
+	ipFamilyNone = 0
+	ipFamily4    = 4
+	ipFamily6    = 6
+)
+
+func ipValue(t Type, addr netip.Addr, aux uint16) Value {
+	v := Value{bits: bits(t) | bitsConst | bits(aux)<<bitsAuxShift}
+	switch {
+	case addr.Is4():
+		a := addr.As4()
+		v.bits |= ipFamily4 << bitsFlagsShift
+		v.vInt = int64(binary.BigEndian.Uint32(a[:]))
+	case addr.Is6():
+		a := addr.As16()
+		v.bits |= ipFamily6 << bitsFlagsShift
+		v.vInt = int64(binary.BigEndian.Uint64(a[:8]))
+		v.vExt = int64(binary.BigEndian.Uint64(a[8:]))
+		v.vString = addr.Zone()
+	}
+
+	return v
+}
+
+func (v Value) ip() netip.Addr {
+	switch v.bits.flags() {
+	case ipFamily4:
+		var a [4]byte
+		binary.BigEndian.PutUint32(a[:], uint32(v.vInt))
+
+		return netip.AddrFrom4(a)
+	case ipFamily6:
+		var a [16]byte
+		binary.BigEndian.PutUint64(a[:8], uint64(v.vInt))
+		binary.BigEndian.PutUint64(a[8:], uint64(v.vExt))
+
+		return netip.AddrFrom16(a).WithZone(v.vString)
+	}
+
+	return netip.Addr{}
+}
+
+func (v Value) ipPort() netip.AddrPort {
+	return netip.AddrPortFrom(v.ip(), v.bits.aux())
+}
+
+func (v Value) ipPrefix() netip.Prefix {
+	if v.bits.flags() == ipFamilyNone {
+		return netip.Prefix{}
+	}
+
+	return netip.PrefixFrom(v.ip(), int(int16(v.bits.aux())))
+}
+
+func (v Value) uuid() [16]byte {
+	var u [16]byte
+	binary.BigEndian.PutUint64(u[:8], uint64(v.vInt))
+	binary.BigEndian.PutUint64(u[8:], uint64(v.vExt))
+
+	return u
+}
+
+func (v Value) acceptNetVisitor(visitor Visitor) {
+	nv, ok := visitor.(NetVisitor)
+	switch v.bits.Type() {
+	case TypeIP:
+		if ok {
+			nv.VisitIP(v.ip())
+		} else {
+			visitor.VisitString(v.ip().String())
+		}
+	case TypeIPPort:
+		if ok {
+			nv.VisitIPPort(v.ipPort())
+		} else {
+			visitor.VisitString(v.ipPort().String())
+		}
+	case TypeIPPrefix:
+		if ok {
+			nv.VisitIPPrefix(v.ipPrefix())
+		} else {
+			visitor.VisitString(v.ipPrefix().String())
+		}
+	case TypeMAC:
+		if ok {
+			nv.VisitMAC(v.vBytes)
+		} else {
+			visitor.VisitString(net.HardwareAddr(v.vBytes).String())
+		}
+	}
+}
+
+func (v Value) acceptUUIDVisitor(visitor Visitor) {
+	if uv, ok := visitor.(UUIDVisitor); ok {
+		uv.VisitUUID(v.uuid())
+	} else {
+		visitor.VisitString(FormatUUID(v.uuid()))
+	}
+}
+
+func (v Value) acceptEnumVisitor(visitor Visitor) {
+	if ev, ok := visitor.(EnumVisitor); ok {
+		ev.VisitEnum(v.vInt, v.vString)
+	} else {
+		visitor.VisitString(v.vString)
+	}
+}
+
+func (v Value) acceptSliceVisitor(visitor Visitor) {
+	sv, ok := visitor.(SliceVisitor)
+	switch v.bits.Type() {
+	case TypeTimes:
+		if ok {
+			sv.VisitTimes(v.vAny.([]time.Time))
+		} else {
+			visitor.VisitArray(timesArray(v.vAny.([]time.Time)))
+		}
+	case TypeErrors:
+		if ok {
+			sv.VisitErrors(v.vAny.([]error))
+		} else {
+			visitor.VisitArray(errorsArray{v.vAny.([]error), v.bits.Const()})
+		}
+	case TypeStringers:
+		if ok {
+			sv.VisitStringers(v.vAny.([]fmt.Stringer))
+		} else {
+			visitor.VisitArray(stringersArray{v.vAny.([]fmt.Stringer), v.bits.Const()})
+		}
+	}
+}
+
+type timesArray []time.Time
+
+func (a timesArray) ArrayItemCount() int {
+	return len(a)
+}
+
+func (a timesArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i, item := range a {
+		visitor.VisitArrayItem(i, Time(item))
+	}
+}
+
+type errorsArray struct {
+	items   []error
+	isConst bool
+}
+
+func (a errorsArray) ArrayItemCount() int {
+	return len(a.items)
+}
+
+func (a errorsArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i, item := range a.items {
+		if a.isConst {
+			visitor.VisitArrayItem(i, ConstError(item))
+		} else {
+			visitor.VisitArrayItem(i, Error(item))
+		}
+	}
+}
+
+type stringersArray struct {
+	items   []fmt.Stringer
+	isConst bool
+}
+
+func (a stringersArray) ArrayItemCount() int {
+	return len(a.items)
+}
+
+func (a stringersArray) AcceptArrayItemVisitor(visitor ArrayItemVisitor) {
+	for i, item := range a.items {
+		if a.isConst {
+			visitor.VisitArrayItem(i, ConstStringer(item))
+		} else {
+			visitor.VisitArrayItem(i, Stringer(item))
+		}
+	}
+}
+
+// FormatUUID returns the canonical text representation of the given UUID,
+// e.g. "123e4567-e89b-12d3-a456-426614174000".
+func FormatUUID(v [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], v[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], v[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], v[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], v[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], v[10:])
+
+	return string(buf[:])
+}
+
+// FormatDecimal returns a string representation of the decimal number equal
+// to coefficient * 10^exponent in plain notation, e.g. "-12.345", or in
+// exponent notation, e.g. "1.2e+40", if the plain notation would need more
+// than 32 padding zeros.
+// It can be used by encoders that lack native support of decimal numbers.
+func FormatDecimal(coefficient *big.Int, exponent int32) string {
+	if coefficient == nil {
+		return "<nil>"
+	}
+
+	digits := new(big.Int).Abs(coefficient).String()
+	sign := ""
+	if coefficient.Sign() < 0 {
+		sign = "-"
+	}
+
+	if exponent >= 0 {
+		if coefficient.Sign() == 0 {
+			return "0"
+		}
+		if exponent > maxDecimalPadding {
+			return sign + formatDecimalExp(digits, exponent)
+		}
+
+		return sign + digits + strings.Repeat("0", int(exponent))
+	}
+
+	scale := -int64(exponent)
+	if int64(len(digits)) <= scale {
+		padding := scale - int64(len(digits)) + 1
+		if padding > maxDecimalPadding {
+			return sign + formatDecimalExp(digits, exponent)
+		}
+		digits = strings.Repeat("0", int(padding)) + digits
+	}
+	point := len(digits) - int(scale)
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// maxDecimalPadding is the maximum number of zeros FormatDecimal adds
+// to the digits of a coefficient before switching to exponent notation.
+const maxDecimalPadding = 32
+
+// formatDecimalExp formats digits * 10^exponent in exponent notation.
+func formatDecimalExp(digits string, exponent int32) string {
+	e := int64(exponent) + int64(len(digits)) - 1
+	mantissa := digits[:1]
+	if len(digits) > 1 {
+		mantissa += "." + digits[1:]
+	}
+	if e < 0 {
+		return mantissa + "e" + strconv.FormatInt(e, 10)
+	}
+
+	return mantissa + "e+" + strconv.FormatInt(e, 10)
+}
+
+type bits uint32
 
 const (
 	bitsMaskType bits = (1 << 7) - 1
 	bitsConst    bits = 1 << 7
+
+	// Flags and auxiliary data are used by some types to keep
+	// their data inline in a Value.
+	bitsFlagsShift = 8
+	bitsAuxShift   = 16
 )
 
 func (b bits) Type() Type {
@@ -774,3 +1556,11 @@ func (b bits) Type() Type {
 func (b bits) Const() bool {
 	return b&bitsConst != 0
 }
+
+func (b bits) flags() uint8 {
+	return uint8(b >> bitsFlagsShift)
+}
+
+func (b bits) aux() uint16 {
+	return uint16(b >> bitsAuxShift)
+}
@@ -3,6 +3,11 @@ package valf
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"strings"
 	"testing"
 	"time"
 
@@ -1480,6 +1485,17 @@ func TestValueError(t *testing.T) {
 	err := errors.New("some error")
 	value := Error(err)
 	require.Equal(t, TypeError, value.Type())
+	require.Equal(t, false, value.Const())
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, err, visitor.value)
+}
+
+func TestValueConstError(t *testing.T) {
+	visitor := newMockErrorVisitor(t)
+	err := errors.New("some error")
+	value := ConstError(err)
+	require.Equal(t, TypeError, value.Type())
 	require.Equal(t, true, value.Const())
 	value.AcceptVisitor(visitor)
 	require.Equal(t, true, visitor.visited)
@@ -1502,7 +1518,7 @@ func TestValueAnyError(t *testing.T) {
 	err := errors.New("some error")
 	value := Any(err)
 	require.Equal(t, TypeError, value.Type())
-	require.Equal(t, true, value.Const())
+	require.Equal(t, false, value.Const())
 	value.AcceptVisitor(visitor)
 	require.Equal(t, true, visitor.visited)
 	require.Equal(t, err, visitor.value)
@@ -2627,3 +2643,641 @@ func TestValueConstAnyEmptyStruct(t *testing.T) {
 	require.Equal(t, true, visitor.visited)
 	require.Equal(t, v, visitor.value)
 }
+
+func newMockBigVisitor(t *testing.T) *mockBigVisitor {
+	return &mockBigVisitor{mockVisitor: mockVisitor{t}}
+}
+
+type mockBigVisitor struct {
+	mockVisitor
+	value    interface{}
+	exponent int32
+	visited  bool
+}
+
+func (v *mockBigVisitor) VisitBigInt(value *big.Int) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockBigVisitor) VisitBigFloat(value *big.Float) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockBigVisitor) VisitBigRat(value *big.Rat) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockBigVisitor) VisitDecimal(coefficient *big.Int, exponent int32) {
+	v.value = coefficient
+	v.exponent = exponent
+	v.visited = true
+}
+
+type testDecimal struct {
+	coefficient int64
+	exponent    int32
+}
+
+func (d testDecimal) Coefficient() *big.Int {
+	return big.NewInt(d.coefficient)
+}
+
+func (d testDecimal) Exponent() int32 {
+	return d.exponent
+}
+
+func TestValueBigInt(t *testing.T) {
+	v, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	value := BigInt(v)
+	require.Equal(t, TypeBigInt, value.Type())
+	require.Equal(t, false, value.Const())
+
+	visitor := newMockBigVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, v, visitor.value)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "123456789012345678901234567890", stringVisitor.value)
+
+	require.Equal(t, true, ConstBigInt(v).Const())
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstBigInt(v), ConstAny(v))
+}
+
+func TestValueBigFloat(t *testing.T) {
+	v := big.NewFloat(1.5)
+	value := BigFloat(v)
+	require.Equal(t, TypeBigFloat, value.Type())
+	require.Equal(t, false, value.Const())
+
+	visitor := newMockBigVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "1.5", stringVisitor.value)
+
+	require.Equal(t, true, ConstBigFloat(v).Const())
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstBigFloat(v), ConstAny(v))
+}
+
+func TestValueBigRat(t *testing.T) {
+	v := big.NewRat(3, 4)
+	value := BigRat(v)
+	require.Equal(t, TypeBigRat, value.Type())
+	require.Equal(t, false, value.Const())
+
+	visitor := newMockBigVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "3/4", stringVisitor.value)
+
+	require.Equal(t, true, ConstBigRat(v).Const())
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstBigRat(v), ConstAny(v))
+}
+
+func TestValueDecimal(t *testing.T) {
+	v := big.NewInt(-12345)
+	value := Decimal(v, -3)
+	require.Equal(t, TypeDecimal, value.Type())
+	require.Equal(t, false, value.Const())
+
+	visitor := newMockBigVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+	require.Equal(t, int32(-3), visitor.exponent)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "-12.345", stringVisitor.value)
+
+	require.Equal(t, true, ConstDecimal(v, -3).Const())
+	require.Equal(t, Decimal(big.NewInt(42), 2), Any(testDecimal{42, 2}))
+	require.Equal(t, ConstDecimal(big.NewInt(42), 2), ConstAny(testDecimal{42, 2}))
+}
+
+func TestValueNilBig(t *testing.T) {
+	for _, value := range []Value{BigInt(nil), BigFloat(nil), BigRat(nil), Decimal(nil, 0)} {
+		visitor := newMockAnyVisitor(t)
+		require.Equal(t, true, value.Const())
+		value.AcceptVisitor(visitor)
+		require.Equal(t, true, visitor.visited)
+		require.Equal(t, nil, visitor.value)
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	require.Equal(t, "12.345", FormatDecimal(big.NewInt(12345), -3))
+	require.Equal(t, "-0.012", FormatDecimal(big.NewInt(-12), -3))
+	require.Equal(t, "0.0", FormatDecimal(big.NewInt(0), -1))
+	require.Equal(t, "4200", FormatDecimal(big.NewInt(42), 2))
+	require.Equal(t, "0", FormatDecimal(big.NewInt(0), 2))
+	require.Equal(t, "42", FormatDecimal(big.NewInt(42), 0))
+	require.Equal(t, "<nil>", FormatDecimal(nil, 0))
+	require.Equal(t, "1"+strings.Repeat("0", 32), FormatDecimal(big.NewInt(1), 32))
+	require.Equal(t, "1.2e+34", FormatDecimal(big.NewInt(12), 33))
+	require.Equal(t, "-1.2e+2147483648", FormatDecimal(big.NewInt(-12), math.MaxInt32))
+	require.Equal(t, "0."+strings.Repeat("0", 31)+"1", FormatDecimal(big.NewInt(1), -32))
+	require.Equal(t, "1e-33", FormatDecimal(big.NewInt(1), -33))
+	require.Equal(t, "-1.23e-2147483646", FormatDecimal(big.NewInt(-123), math.MinInt32))
+	require.Equal(t, "12345.6", FormatDecimal(big.NewInt(123456), -1))
+}
+
+func newMockComplexVisitor(t *testing.T) *mockComplexVisitor {
+	return &mockComplexVisitor{mockVisitor: mockVisitor{t}}
+}
+
+type mockComplexVisitor struct {
+	mockVisitor
+	value   interface{}
+	visited bool
+}
+
+func (v *mockComplexVisitor) VisitComplex64(value complex64) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockComplexVisitor) VisitComplex128(value complex128) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockComplexVisitor) VisitComplexes64(value []complex64) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockComplexVisitor) VisitComplexes128(value []complex128) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockComplexVisitor) VisitUintptr(value uintptr) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockComplexVisitor) VisitUintptrs(value []uintptr) {
+	v.value = value
+	v.visited = true
+}
+
+func TestValueComplex64(t *testing.T) {
+	v := complex64(complex(1.5, -2))
+	value := Complex64(v)
+	require.Equal(t, TypeComplex64, value.Type())
+	require.Equal(t, true, value.Const())
+
+	visitor := newMockComplexVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, v, visitor.value)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "(1.5-2i)", stringVisitor.value)
+
+	require.Equal(t, value, Any(v))
+	require.Equal(t, value, ConstAny(v))
+}
+
+// valueSink keeps values created in allocation tests from being optimized away.
+var valueSink Value
+
+func TestValueComplex128(t *testing.T) {
+	v := complex(1.25, 3)
+	value := Complex128(v)
+	require.Equal(t, TypeComplex128, value.Type())
+	require.Equal(t, true, value.Const())
+
+	visitor := newMockComplexVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, v, visitor.value)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "(1.25+3i)", stringVisitor.value)
+
+	require.Equal(t, value, Any(v))
+	require.Equal(t, value, ConstAny(v))
+
+	require.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+		valueSink = Complex128(v)
+	}))
+}
+
+func TestValueUintptr(t *testing.T) {
+	v := uintptr(42)
+	value := Uintptr(v)
+	require.Equal(t, TypeUintptr, value.Type())
+	require.Equal(t, true, value.Const())
+
+	visitor := newMockComplexVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, v, visitor.value)
+
+	uintVisitor := newMockUint64Visitor(t)
+	value.AcceptVisitor(uintVisitor)
+	require.Equal(t, uint64(42), uintVisitor.value)
+
+	require.Equal(t, value, Any(v))
+	require.Equal(t, value, ConstAny(v))
+}
+
+func TestValueComplexes64(t *testing.T) {
+	v := []complex64{1, complex(0, 1)}
+	value := Complexes64(v)
+	require.Equal(t, TypeComplexes64, value.Type())
+	require.Equal(t, false, value.Const())
+
+	visitor := newMockComplexVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	stringsVisitor := newMockStringsVisitor(t)
+	value.AcceptVisitor(stringsVisitor)
+	require.Equal(t, []string{"(1+0i)", "(0+1i)"}, stringsVisitor.value)
+
+	require.Equal(t, true, ConstComplexes64(v).Const())
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstComplexes64(v), ConstAny(v))
+}
+
+func TestValueComplexes128(t *testing.T) {
+	v := []complex128{1, complex(0, 1)}
+	value := Complexes128(v)
+	require.Equal(t, TypeComplexes128, value.Type())
+	require.Equal(t, false, value.Const())
+
+	visitor := newMockComplexVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	stringsVisitor := newMockStringsVisitor(t)
+	value.AcceptVisitor(stringsVisitor)
+	require.Equal(t, []string{"(1+0i)", "(0+1i)"}, stringsVisitor.value)
+
+	require.Equal(t, true, ConstComplexes128(v).Const())
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstComplexes128(v), ConstAny(v))
+}
+
+func TestValueUintptrs(t *testing.T) {
+	v := []uintptr{1, 2}
+	value := Uintptrs(v)
+	require.Equal(t, TypeUintptrs, value.Type())
+	require.Equal(t, false, value.Const())
+
+	visitor := newMockComplexVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	uintsVisitor := newMockUintsVisitor(t)
+	value.AcceptVisitor(uintsVisitor)
+	require.Equal(t, []uint{1, 2}, uintsVisitor.value)
+
+	require.Equal(t, true, ConstUintptrs(v).Const())
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstUintptrs(v), ConstAny(v))
+}
+
+type customComplex64 complex64
+
+type customComplex128 complex128
+
+type customUintptr uintptr
+
+func TestValueAnyCustomComplex(t *testing.T) {
+	require.Equal(t, Complex64(complex(1, 2)), Any(customComplex64(complex(1, 2))))
+	require.Equal(t, Complex64(complex(1, 2)), ConstAny(customComplex64(complex(1, 2))))
+	require.Equal(t, Complex128(complex(1, 2)), Any(customComplex128(complex(1, 2))))
+	require.Equal(t, Complex128(complex(1, 2)), ConstAny(customComplex128(complex(1, 2))))
+	require.Equal(t, Uintptr(42), Any(customUintptr(42)))
+	require.Equal(t, Uintptr(42), ConstAny(customUintptr(42)))
+}
+
+func newMockNetVisitor(t *testing.T) *mockNetVisitor {
+	return &mockNetVisitor{mockVisitor: mockVisitor{t}}
+}
+
+type mockNetVisitor struct {
+	mockVisitor
+	value   interface{}
+	visited bool
+}
+
+func (v *mockNetVisitor) VisitIP(value netip.Addr) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockNetVisitor) VisitIPPort(value netip.AddrPort) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockNetVisitor) VisitIPPrefix(value netip.Prefix) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockNetVisitor) VisitMAC(value net.HardwareAddr) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockNetVisitor) VisitUUID(value [16]byte) {
+	v.value = value
+	v.visited = true
+}
+
+func TestValueIP(t *testing.T) {
+	for _, v := range []netip.Addr{
+		netip.MustParseAddr("192.168.1.1"),
+		netip.MustParseAddr("2001:db8::1"),
+		netip.MustParseAddr("fe80::1%eth0"),
+		netip.MustParseAddr("::ffff:10.0.0.1"),
+		{},
+	} {
+		value := IP(v)
+		require.Equal(t, TypeIP, value.Type())
+		require.Equal(t, true, value.Const())
+
+		visitor := newMockNetVisitor(t)
+		value.AcceptVisitor(visitor)
+		require.Equal(t, true, visitor.visited)
+		require.Equal(t, v, visitor.value)
+
+		stringVisitor := newMockStringVisitor(t)
+		value.AcceptVisitor(stringVisitor)
+		require.Equal(t, v.String(), stringVisitor.value)
+
+		require.Equal(t, value, Any(v))
+		require.Equal(t, value, ConstAny(v))
+	}
+}
+
+func TestValueIPPort(t *testing.T) {
+	for _, v := range []netip.AddrPort{
+		netip.MustParseAddrPort("10.0.0.1:8080"),
+		netip.MustParseAddrPort("[2001:db8::1]:65535"),
+		{},
+	} {
+		value := IPPort(v)
+		require.Equal(t, TypeIPPort, value.Type())
+		require.Equal(t, true, value.Const())
+
+		visitor := newMockNetVisitor(t)
+		value.AcceptVisitor(visitor)
+		require.Equal(t, v, visitor.value)
+
+		stringVisitor := newMockStringVisitor(t)
+		value.AcceptVisitor(stringVisitor)
+		require.Equal(t, v.String(), stringVisitor.value)
+
+		require.Equal(t, value, Any(v))
+		require.Equal(t, value, ConstAny(v))
+	}
+}
+
+func TestValueIPPrefix(t *testing.T) {
+	for _, v := range []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8::/32"),
+		netip.PrefixFrom(netip.MustParseAddr("10.0.0.1"), 33),
+		{},
+	} {
+		value := IPPrefix(v)
+		require.Equal(t, TypeIPPrefix, value.Type())
+		require.Equal(t, true, value.Const())
+
+		visitor := newMockNetVisitor(t)
+		value.AcceptVisitor(visitor)
+		require.Equal(t, v, visitor.value)
+
+		stringVisitor := newMockStringVisitor(t)
+		value.AcceptVisitor(stringVisitor)
+		require.Equal(t, v.String(), stringVisitor.value)
+
+		require.Equal(t, value, Any(v))
+		require.Equal(t, value, ConstAny(v))
+	}
+}
+
+func TestValueMAC(t *testing.T) {
+	v := net.HardwareAddr{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}
+	value := MAC(v)
+	require.Equal(t, TypeMAC, value.Type())
+	require.Equal(t, false, value.Const())
+	require.Equal(t, true, ConstMAC(v).Const())
+	require.Equal(t, true, MAC(nil).Const())
+
+	visitor := newMockNetVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "00:1a:2b:3c:4d:5e", stringVisitor.value)
+
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstMAC(v), ConstAny(v))
+}
+
+type testUUID [16]byte
+
+func TestValueUUID(t *testing.T) {
+	v := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	value := UUID(v)
+	require.Equal(t, TypeUUID, value.Type())
+	require.Equal(t, true, value.Const())
+
+	visitor := newMockNetVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, v, visitor.value)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "123e4567-e89b-12d3-a456-426614174000", stringVisitor.value)
+
+	require.Equal(t, value, Any(testUUID(v)))
+	require.Equal(t, value, ConstAny(testUUID(v)))
+	require.Equal(t, value, Any(testStringerUUID(v)))
+	require.Equal(t, value, ConstAny(testStringerUUID(v)))
+	require.Equal(t, TypeStringer, Any(testStringer("UUID")).Type())
+}
+
+func TestValueNetAllocations(t *testing.T) {
+	u := [16]byte{1, 2, 3}
+	addr := netip.MustParseAddr("2001:db8::1")
+	zoned := netip.MustParseAddr("fe80::1%eth0")
+	port := netip.AddrPortFrom(addr, 80)
+	prefix := netip.PrefixFrom(addr, 32)
+
+	require.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+		valueSink = UUID(u)
+		valueSink = IP(addr)
+		valueSink = IP(zoned)
+		valueSink = IPPort(port)
+		valueSink = IPPrefix(prefix)
+	}))
+
+	require.Equal(t, addr, IP(addr).ip())
+	require.Equal(t, zoned, IP(zoned).ip())
+	require.Equal(t, u, UUID(u).uuid())
+}
+
+type testStringerUUID [16]byte
+
+func (u testStringerUUID) String() string {
+	return FormatUUID(u)
+}
+
+type mockEnumVisitor struct {
+	mockVisitor
+	value   int64
+	name    string
+	visited bool
+}
+
+func newMockEnumVisitor(t *testing.T) *mockEnumVisitor {
+	return &mockEnumVisitor{mockVisitor: mockVisitor{t}}
+}
+
+func (v *mockEnumVisitor) VisitEnum(value int64, name string) {
+	v.value = value
+	v.name = name
+	v.visited = true
+}
+
+type testLevel int
+
+func (l testLevel) String() string {
+	return [...]string{"debug", "info", "error"}[l]
+}
+
+func (l testLevel) EnumValue() int64 {
+	return int64(l)
+}
+
+func TestValueEnum(t *testing.T) {
+	value := Enum(2, "error")
+	require.Equal(t, TypeEnum, value.Type())
+	require.Equal(t, true, value.Const())
+
+	visitor := newMockEnumVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, int64(2), visitor.value)
+	require.Equal(t, "error", visitor.name)
+
+	stringVisitor := newMockStringVisitor(t)
+	value.AcceptVisitor(stringVisitor)
+	require.Equal(t, "error", stringVisitor.value)
+
+	require.Equal(t, value, Any(testLevel(2)))
+	require.Equal(t, value, ConstAny(testLevel(2)))
+	require.Equal(t, value, value.Snapshot())
+}
+
+type mockSliceVisitor struct {
+	mockVisitor
+	value   interface{}
+	visited bool
+}
+
+func newMockSliceVisitor(t *testing.T) *mockSliceVisitor {
+	return &mockSliceVisitor{mockVisitor: mockVisitor{t}}
+}
+
+func (v *mockSliceVisitor) VisitTimes(value []time.Time) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockSliceVisitor) VisitErrors(value []error) {
+	v.value = value
+	v.visited = true
+}
+
+func (v *mockSliceVisitor) VisitStringers(value []fmt.Stringer) {
+	v.value = value
+	v.visited = true
+}
+
+func TestValueTimes(t *testing.T) {
+	v := []time.Time{time.Unix(1, 0).UTC(), time.Unix(2, 0).UTC()}
+	value := Times(v)
+	require.Equal(t, TypeTimes, value.Type())
+	require.Equal(t, false, value.Const())
+	require.Equal(t, true, ConstTimes(v).Const())
+
+	visitor := newMockSliceVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, true, visitor.visited)
+	require.Equal(t, v, visitor.value)
+
+	arrayVisitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(arrayVisitor)
+	require.Equal(t, []Value{Time(v[0]), Time(v[1])}, arrayVisitor.value)
+
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstTimes(v), ConstAny(v))
+}
+
+func TestValueErrors(t *testing.T) {
+	v := []error{errors.New("a"), nil}
+	value := Errors(v)
+	require.Equal(t, TypeErrors, value.Type())
+	require.Equal(t, false, value.Const())
+	require.Equal(t, true, ConstErrors(v).Const())
+
+	visitor := newMockSliceVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	arrayVisitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(arrayVisitor)
+	require.Equal(t, []Value{Error(v[0]), Error(nil)}, arrayVisitor.value)
+
+	arrayVisitor = newMockArrayVisitor(t)
+	ConstErrors(v).AcceptVisitor(arrayVisitor)
+	require.Equal(t, []Value{ConstError(v[0]), ConstError(nil)}, arrayVisitor.value)
+
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstErrors(v), ConstAny(v))
+}
+
+func TestValueStringers(t *testing.T) {
+	v := []fmt.Stringer{time.Second, nil}
+	value := Stringers(v)
+	require.Equal(t, TypeStringers, value.Type())
+	require.Equal(t, false, value.Const())
+	require.Equal(t, true, ConstStringers(v).Const())
+
+	visitor := newMockSliceVisitor(t)
+	value.AcceptVisitor(visitor)
+	require.Equal(t, v, visitor.value)
+
+	arrayVisitor := newMockArrayVisitor(t)
+	value.AcceptVisitor(arrayVisitor)
+	require.Equal(t, []Value{Stringer(time.Second), Stringer(nil)}, arrayVisitor.value)
+
+	require.Equal(t, value, Any(v))
+	require.Equal(t, ConstStringers(v), ConstAny(v))
+}
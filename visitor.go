@@ -1,6 +1,10 @@
 package valf
 
 import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
 	"time"
 )
 
@@ -46,6 +50,90 @@ type Visitor interface {
 	VisitObject(ValueObject)
 }
 
+// BigVisitor is an optional extension of Visitor interface which allows to
+// visit arbitrary-precision numbers. Visitors that do not implement
+// BigVisitor get such numbers as strings via VisitString.
+type BigVisitor interface {
+	VisitBigInt(*big.Int)
+	VisitBigFloat(*big.Float)
+	VisitBigRat(*big.Rat)
+	VisitDecimal(coefficient *big.Int, exponent int32)
+}
+
+// ComplexVisitor is an optional extension of Visitor interface which allows
+// to visit complex numbers. Visitors that do not implement ComplexVisitor
+// get such numbers as strings via VisitString and VisitStrings.
+type ComplexVisitor interface {
+	VisitComplex64(complex64)
+	VisitComplex128(complex128)
+	VisitComplexes64([]complex64)
+	VisitComplexes128([]complex128)
+}
+
+// UintptrVisitor is an optional extension of Visitor interface which allows
+// to visit uintptr values. Visitors that do not implement UintptrVisitor
+// get such values via VisitUint64 and VisitUints.
+type UintptrVisitor interface {
+	VisitUintptr(uintptr)
+	VisitUintptrs([]uintptr)
+}
+
+// NetVisitor is an optional extension of Visitor interface which allows
+// to visit network addresses. Visitors that do not implement NetVisitor
+// get such values in text form via VisitString.
+type NetVisitor interface {
+	VisitIP(netip.Addr)
+	VisitIPPort(netip.AddrPort)
+	VisitIPPrefix(netip.Prefix)
+	VisitMAC(net.HardwareAddr)
+}
+
+// UUIDVisitor is an optional extension of Visitor interface which allows
+// to visit UUIDs. Visitors that do not implement UUIDVisitor get such
+// values in the canonical text form via VisitString.
+type UUIDVisitor interface {
+	VisitUUID([16]byte)
+}
+
+// RawVisitor is an optional extension of Visitor interface which allows
+// to visit raw pre-encoded data, e.g. to embed it verbatim if the encoding
+// matches the output format. Visitors that do not implement RawVisitor
+// get JSON data decoded, text data via VisitString and data in other
+// encodings via VisitBytes.
+type RawVisitor interface {
+	VisitRaw(Encoding, []byte)
+}
+
+// EnumVisitor is an optional extension of Visitor interface which allows
+// to visit enum values having both a number and a name. Visitors that do not
+// implement EnumVisitor get the name via VisitString.
+type EnumVisitor interface {
+	VisitEnum(value int64, name string)
+}
+
+// AnnotatedVisitor is an optional extension of Visitor interface which allows
+// to visit annotated values. Visitors that do not implement AnnotatedVisitor
+// visit the inner value as if it had no annotations.
+type AnnotatedVisitor interface {
+	VisitAnnotated(Value, []Annotation)
+}
+
+// NullVisitor is an optional extension of Visitor interface which allows
+// to distinguish explicit null values. Visitors that do not implement
+// NullVisitor get VisitAny(nil) for them.
+type NullVisitor interface {
+	VisitNull()
+}
+
+// SliceVisitor is an optional extension of Visitor interface which allows
+// to visit slices of times, errors and Stringers. Visitors that do not
+// implement SliceVisitor get such slices via VisitArray.
+type SliceVisitor interface {
+	VisitTimes([]time.Time)
+	VisitErrors([]error)
+	VisitStringers([]fmt.Stringer)
+}
+
 // ValueArray accepts ArrayItemVisitor.
 type ValueArray interface {
 	ArrayItemCount() int
@@ -68,6 +156,15 @@ type ObjectFieldVisitor interface {
 	VisitObjectField(key string, value Value)
 }
 
+// StableItems is an optional extension of ValueArray and ValueObject
+// interfaces. Implementing it promises that items remain valid after
+// VisitArrayItem or VisitObjectField returns, i.e. they do not reference
+// memory reused between visits. Such arrays and objects may be snapshotted
+// in parallel, see SnapshotOptions.ParallelThreshold.
+type StableItems interface {
+	StableItems()
+}
+
 // IgnoringVisitor is an implementation of Visitor interface which does nothing.
 type IgnoringVisitor struct{}
 